@@ -14,6 +14,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/19910211/easyjson/parser"
 )
 
 const genPackage = "github.com/19910211/easyjson/gen"
@@ -27,12 +31,26 @@ type Generator struct {
 	Types                    []string
 	PoolStructs              []string
 	CloneStructs             []string
+	EqualStructs             []string
+	MergeStructs             []string
+	Views                    []parser.View
 	NoStdMarshalers          bool
 	SnakeCase                bool
 	LowerCamelCase           bool
 	OmitEmpty                bool
 	DisallowUnknownFields    bool
+	ResetMissingFields       bool
+	OrderedUnknowns          bool
+	DisallowDuplicateKeys    bool
 	SkipMemberNameUnescaping bool
+	FieldMask                bool
+	Schema                   bool
+	EqualJSONOnly            bool
+	Diff                     bool
+	JSONv2                   bool
+	StdStringBools           bool
+	StdDashName              bool
+	StdEmptyArrays           bool
 
 	OutName       string
 	BuildTags     string
@@ -67,6 +85,9 @@ func (g *Generator) writeStub() error {
 		fmt.Fprintln(f, "import (")
 		fmt.Fprintln(f, `  "`+pkgWriter+`"`)
 		fmt.Fprintln(f, `  "`+pkgLexer+`"`)
+		if g.JSONv2 {
+			fmt.Fprintln(f, `  "encoding/json/jsontext"`)
+		}
 		fmt.Fprintln(f, ")")
 	}
 
@@ -80,6 +101,16 @@ func (g *Generator) writeStub() error {
 
 		fmt.Fprintln(f, "func (", t, ") MarshalEasyJSON(w *jwriter.Writer) {}")
 		fmt.Fprintln(f, "func (*", t, ") UnmarshalEasyJSON(l *jlexer.Lexer) {}")
+		if g.JSONv2 {
+			fmt.Fprintln(f, "func (", t, ") MarshalJSONTo(*jsontext.Encoder) error { return nil }")
+			fmt.Fprintln(f, "func (*", t, ") UnmarshalJSONFrom(*jsontext.Decoder) error { return nil }")
+		}
+		for _, v := range g.Views {
+			if v.Type == t {
+				fmt.Fprintln(f, "func (*", t, ") Unmarshal"+v.Name+"([]byte) error { return nil }")
+				fmt.Fprintln(f, "func (*", t, ") UnmarshalEasyJSON"+v.Name+"(l *jlexer.Lexer) {}")
+			}
+		}
 		fmt.Fprintln(f)
 		fmt.Fprintln(f, "type EasyJSON_exporter_"+t+" *"+t)
 	}
@@ -133,12 +164,45 @@ func (g *Generator) writeMain() (path string, err error) {
 	if g.DisallowUnknownFields {
 		fmt.Fprintln(f, "  g.DisallowUnknownFields()")
 	}
+	if g.DisallowDuplicateKeys {
+		fmt.Fprintln(f, "  g.DisallowDuplicateKeys()")
+	}
+	if g.ResetMissingFields {
+		fmt.Fprintln(f, "  g.ResetMissingFields()")
+	}
+	if g.OrderedUnknowns {
+		fmt.Fprintln(f, "  g.OrderedUnknowns()")
+	}
 	if g.SimpleBytes {
 		fmt.Fprintln(f, "  g.SimpleBytes()")
 	}
 	if g.SkipMemberNameUnescaping {
 		fmt.Fprintln(f, "  g.SkipMemberNameUnescaping()")
 	}
+	if g.FieldMask {
+		fmt.Fprintln(f, "  g.SupportFieldMask()")
+	}
+	if g.Schema {
+		fmt.Fprintln(f, "  g.GenerateSchema()")
+	}
+	if g.EqualJSONOnly {
+		fmt.Fprintln(f, "  g.EqualJSONFieldsOnly()")
+	}
+	if g.Diff {
+		fmt.Fprintln(f, "  g.GenerateDiff()")
+	}
+	if g.JSONv2 {
+		fmt.Fprintln(f, "  g.GenerateJSONv2()")
+	}
+	if g.StdStringBools {
+		fmt.Fprintln(f, "  g.StdStringBools()")
+	}
+	if g.StdDashName {
+		fmt.Fprintln(f, "  g.StdDashName()")
+	}
+	if g.StdEmptyArrays {
+		fmt.Fprintln(f, "  g.StdEmptyArrays()")
+	}
 
 	sort.Strings(g.Types)
 	for _, v := range g.Types {
@@ -153,6 +217,22 @@ func (g *Generator) writeMain() (path string, err error) {
 		fmt.Fprintln(f, "  g.AddClone(pkg.EasyJSON_exporter_"+v+"(nil))")
 	}
 
+	for _, v := range g.EqualStructs {
+		fmt.Fprintln(f, "  g.AddEqual(pkg.EasyJSON_exporter_"+v+"(nil))")
+	}
+
+	for _, v := range g.MergeStructs {
+		fmt.Fprintln(f, "  g.AddMerge(pkg.EasyJSON_exporter_"+v+"(nil))")
+	}
+
+	for _, v := range g.Views {
+		fields := make([]string, 0, len(v.Fields))
+		for _, name := range v.Fields {
+			fields = append(fields, strconv.Quote(name))
+		}
+		fmt.Fprintf(f, "  g.AddView(pkg.EasyJSON_exporter_%s(nil), %q, %s)\n", v.Type, v.Name, strings.Join(fields, ", "))
+	}
+
 	fmt.Fprintln(f, "  if err := g.Run(os.Stdout); err != nil {")
 	fmt.Fprintln(f, "    fmt.Fprintln(os.Stderr, err)")
 	fmt.Fprintln(f, "    os.Exit(1)")
@@ -64,6 +64,24 @@ func getBuf(size int) []byte {
 	return make([]byte, 0, size)
 }
 
+// GetChunk returns an empty chunk with room for at least size bytes, reused from the pool if
+// possible. Chunks larger than the maximum chunk size are allocated and never reused.
+func GetChunk(size int) []byte {
+	l := config.PooledSize
+	for l < size && l < config.MaxSize {
+		l *= 2
+	}
+	if l < size {
+		return make([]byte, 0, size)
+	}
+	return getBuf(l)
+}
+
+// PutChunk returns a chunk obtained with GetChunk to the pool. The chunk must not be used after.
+func PutChunk(buf []byte) {
+	putBuf(buf)
+}
+
 // Buffer is a buffer optimized for serialization without extra copying.
 type Buffer struct {
 
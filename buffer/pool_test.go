@@ -105,3 +105,21 @@ func TestReadCloser(t *testing.T) {
 		t.Errorf("DumpTo() = %v; want %v", n, len(want))
 	}
 }
+
+func TestGetChunk(t *testing.T) {
+	for _, tc := range []struct {
+		size, want int
+	}{
+		{size: 1, want: 512},
+		{size: 512, want: 512},
+		{size: 513, want: 1024},
+		{size: 32768, want: 32768},
+		{size: 40000, want: 40000},
+	} {
+		c := GetChunk(tc.size)
+		if len(c) != 0 || cap(c) != tc.want {
+			t.Errorf("GetChunk(%d) len = %d, cap = %d; want 0, %d", tc.size, len(c), cap(c), tc.want)
+		}
+		PutChunk(append(c, 1))
+	}
+}
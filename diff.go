@@ -0,0 +1,84 @@
+package easyjson
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+// Change is a member that differs between two values.
+type Change struct {
+	Path string     // JSON Pointer to the member, "" for the whole value.
+	Old  RawMessage // Encoding of the old value, nil if it was not written.
+	New  RawMessage // Encoding of the new value, nil if it is not written.
+}
+
+// Differ is implemented by types generated with the -diff option.
+type Differ[T any] interface {
+	DiffEasyJSON(path string, other T, changes []Change) []Change
+}
+
+// Diff returns the members that differ between old and new, in the order of
+// struct fields. Nested structs are compared member by member; other values
+// are compared by their JSON encoding.
+func Diff[T Differ[T]](old, new T) []Change {
+	return old.DiffEasyJSON("", new, nil)
+}
+
+// DiffField appends a change at path if the member written by encode differs
+// between old and new. encode reports whether the member was written at all,
+// e.g. false for an empty field with omitempty. It is used by generated code.
+func DiffField[T any](changes []Change, path string, encode func(*jwriter.Writer, *T) bool, old, new *T) []Change {
+	a := encodeMember(encode, old)
+	b := encodeMember(encode, new)
+	if !rawEqual(a, b) {
+		changes = append(changes, Change{Path: path, Old: a, New: b})
+	}
+	return changes
+}
+
+func encodeMember[T any](encode func(*jwriter.Writer, *T) bool, v *T) RawMessage {
+	w := jwriter.Writer{}
+	if !encode(&w, v) {
+		return nil
+	}
+	return w.Buffer.BuildBytes()
+}
+
+// DiffValues appends a change at path if old and new encode differently. A nil
+// value is treated as not written. It is used by generated code.
+func DiffValues(changes []Change, path string, old, new Marshaler) []Change {
+	var a, b RawMessage
+	if !isNilInterface(old) {
+		a, _ = Marshal(old)
+	}
+	if !isNilInterface(new) {
+		b, _ = Marshal(new)
+	}
+	if !rawEqual(a, b) {
+		changes = append(changes, Change{Path: path, Old: a, New: b})
+	}
+	return changes
+}
+
+// rawEqual reports whether two encodings hold the same value. Encodings of
+// maps differ in the order of members, so they are decoded and compared if
+// the bytes do not match. Numbers are compared by their literals, as float64
+// would lose the difference between large integers.
+func rawEqual(a, b RawMessage) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	if bytes.Equal(a, b) {
+		return true
+	}
+	if !bytes.ContainsRune(a, '{') || !bytes.ContainsRune(b, '{') {
+		return false
+	}
+
+	la, lb := jlexer.Lexer{Data: a, UseNumber: true}, jlexer.Lexer{Data: b, UseNumber: true}
+	va, vb := la.Interface(), lb.Interface()
+	return la.Error() == nil && lb.Error() == nil && reflect.DeepEqual(va, vb)
+}
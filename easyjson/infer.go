@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/19910211/easyjson/infer"
+)
+
+// runInfer implements the infer subcommand: it writes Go types for the JSON
+// samples or the JSON Schema given in files.
+func runInfer(args []string) error {
+	fs := flag.NewFlagSet("infer", flag.ExitOnError)
+	fromSchema := fs.Bool("schema", false, "read a JSON Schema instead of sample documents")
+	pkgName := fs.String("pkg", "main", "package name of the generated file")
+	typeName := fs.String("type", "", "name of the document type (default: schema title or Root)")
+	output := fs.String("o", "", "output file (default: standard output)")
+	easyJSON := fs.Bool("easyjson", false, "add //easyjson:json comments to the generated types")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: easyjson infer [flags] file...")
+		fmt.Fprintln(fs.Output(), "Sample files may contain several concatenated documents, e.g. JSON lines.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no input files")
+	}
+	opts := infer.Options{Package: *pkgName, TypeName: *typeName, EasyJSON: *easyJSON}
+
+	var src []byte
+	var err error
+	if *fromSchema {
+		if fs.NArg() != 1 {
+			return errors.New("a single schema file is expected")
+		}
+		data, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		src, err = infer.FromSchema(data, opts)
+		if err != nil {
+			return fmt.Errorf("%v: %v", fs.Arg(0), err)
+		}
+	} else {
+		var samples [][]byte
+		for _, fname := range fs.Args() {
+			docs, err := readSamples(fname)
+			if err != nil {
+				return fmt.Errorf("%v: %v", fname, err)
+			}
+			samples = append(samples, docs...)
+		}
+		if src, err = infer.FromSamples(samples, opts); err != nil {
+			return err
+		}
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0644)
+}
+
+// readSamples returns the JSON documents found in the file.
+func readSamples(fname string) ([][]byte, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var docs [][]byte
+	dec := json.NewDecoder(f)
+	for {
+		var doc json.RawMessage
+		if err := dec.Decode(&doc); err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
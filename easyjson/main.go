@@ -24,6 +24,9 @@ var noStdMarshalers = flag.Bool("no_std_marshalers", false, "don't generate Mars
 var omitEmpty = flag.Bool("omit_empty", false, "omit empty fields by default")
 var allStructs = flag.Bool("all", false, "generate marshaler/unmarshalers for all structs in a file")
 var cloneStructs = flag.Bool("clone", false, "generate clone for all structs in a file")
+var equalStructs = flag.Bool("equal", false, "generate Equal method for all structs in a file")
+var equalJSONOnly = flag.Bool("equal_json_only", false, "ignore fields not encoded to JSON in generated Equal methods")
+var mergeStructs = flag.Bool("merge", false, "generate Merge method overlaying non-empty fields for all structs in a file")
 var simpleBytes = flag.Bool("byte", false, "use simple bytes instead of Base64Bytes for slice of bytes")
 var leaveTemps = flag.Bool("leave_temps", false, "do not delete temporary files")
 var stubs = flag.Bool("stubs", false, "only generate stubs for marshaler/unmarshaler funcs")
@@ -31,7 +34,17 @@ var noformat = flag.Bool("noformat", false, "do not run 'gofmt -w' on output fil
 var specifiedName = flag.String("output_filename", "", "specify the filename of the output")
 var processPkg = flag.Bool("pkg", false, "process the whole package instead of just the given file")
 var disallowUnknownFields = flag.Bool("disallow_unknown_fields", false, "return error if any unknown field in json appeared")
+var disallowDuplicateKeys = flag.Bool("disallow_duplicate_keys", false, "return error if an object in json contains the same key twice")
+var resetMissingFields = flag.Bool("reset_missing", false, "zero the fields missing from json when decoding into an existing value")
+var orderedUnknowns = flag.Bool("ordered_unknowns", false, "keep unknown fields in their position among the known ones, for types implementing easyjson.OrderedUnknownsMarshaler")
 var skipMemberNameUnescaping = flag.Bool("disable_members_unescape", false, "don't perform unescaping of member names to improve performance")
+var schema = flag.Bool("schema", false, "generate JSONSchema methods returning JSON Schema (draft 2020-12) of the types")
+var diff = flag.Bool("diff", false, "generate DiffEasyJSON methods listing the members that differ between two values")
+var jsonv2 = flag.Bool("jsonv2", false, "generate MarshalJSONTo/UnmarshalJSONFrom methods for encoding/json/v2 (needs GOEXPERIMENT=jsonv2)")
+var stdStringBools = flag.Bool("std_string_bools", false, "apply the ,string option to bool fields, as encoding/json does")
+var stdDashName = flag.Bool("std_dash_name", false, "name fields tagged json:\"-,\" \"-\", as encoding/json does, rather than omitting them")
+var stdEmptyArrays = flag.Bool("std_empty_arrays", false, "treat zero-length arrays as empty for omitempty, as encoding/json does")
+var fieldMask = flag.Bool("field_mask", false, "generate encoders honoring the field mask set on jwriter.Writer")
 
 func generate(fname string) (err error) {
 	fInfo, err := os.Stat(fname)
@@ -77,6 +90,14 @@ func generate(fname string) (err error) {
 	if p.CloneStructs {
 		cloneStructList = p.StructNames
 	}
+	var equalStructList []string
+	if *equalStructs {
+		equalStructList = p.StructNames
+	}
+	var mergeStructList []string
+	if *mergeStructs {
+		mergeStructList = p.StructNames
+	}
 
 	g := bootstrap.Generator{
 		BuildTags:                trimmedBuildTags,
@@ -86,11 +107,25 @@ func generate(fname string) (err error) {
 		Types:                    p.StructNames,
 		PoolStructs:              poolStructs,
 		CloneStructs:             cloneStructList,
+		EqualStructs:             equalStructList,
+		MergeStructs:             mergeStructList,
+		Views:                    p.Views,
 		SnakeCase:                *snakeCase,
 		LowerCamelCase:           *lowerCamelCase,
 		NoStdMarshalers:          *noStdMarshalers,
 		DisallowUnknownFields:    *disallowUnknownFields,
+		ResetMissingFields:       *resetMissingFields,
+		DisallowDuplicateKeys:    *disallowDuplicateKeys,
+		OrderedUnknowns:          *orderedUnknowns,
 		SkipMemberNameUnescaping: *skipMemberNameUnescaping,
+		FieldMask:                *fieldMask,
+		Schema:                   *schema,
+		EqualJSONOnly:            *equalJSONOnly,
+		Diff:                     *diff,
+		JSONv2:                   *jsonv2,
+		StdStringBools:           *stdStringBools,
+		StdDashName:              *stdDashName,
+		StdEmptyArrays:           *stdEmptyArrays,
 		OmitEmpty:                *omitEmpty,
 		LeaveTemps:               *leaveTemps,
 		OutName:                  outName,
@@ -106,6 +141,14 @@ func generate(fname string) (err error) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "infer" {
+		if err := runInfer(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	flag.Parse()
 
 	files := flag.Args()
@@ -7,9 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
 	"slices"
 	"strings"
-	"unicode"
 
 	"github.com/19910211/easyjson"
 )
@@ -40,6 +40,7 @@ var primitiveDecoders = map[reflect.Kind]string{
 
 var primitiveStringDecoders = map[reflect.Kind]string{
 	reflect.String:  "in.String()",
+	reflect.Bool:    "in.BoolStr()",
 	reflect.Int:     "in.IntStr()",
 	reflect.Int8:    "in.Int8Str()",
 	reflect.Int16:   "in.Int16Str()",
@@ -73,6 +74,16 @@ func (g *Generator) genTypeDecoder(t reflect.Type, out string, tags fieldTags, i
 		return nil
 	}
 
+	if g.usesJSONv2(t) && hasUnmarshalerFrom(t) {
+		g.imports[pkgJSONv2] = "jsonv2"
+		fmt.Fprintln(g.out, ws+"if in.IsNull() {")
+		fmt.Fprintln(g.out, ws+"  in.Skip()")
+		fmt.Fprintln(g.out, ws+"} else {")
+		fmt.Fprintln(g.out, ws+"  jsonv2.Decode(in, &("+out+"))")
+		fmt.Fprintln(g.out, ws+"}")
+		return nil
+	}
+
 	unmarshalerIface = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
 	if reflect.PtrTo(t).Implements(unmarshalerIface) {
 		fmt.Fprintln(g.out, ws+"if in.IsNull() {")
@@ -109,6 +120,12 @@ func hasCustomUnmarshaler(t reflect.Type) bool {
 		t.Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
 }
 
+// hasUnmarshalerFrom reports whether *t implements json.UnmarshalerFrom of
+// encoding/json/v2.
+func hasUnmarshalerFrom(t reflect.Type) bool {
+	return hasJSONv2Method(reflect.PtrTo(t), "UnmarshalJSONFrom", "Decoder")
+}
+
 func hasUnknownsUnmarshaler(t reflect.Type) bool {
 	t = reflect.PtrTo(t)
 	return t.Implements(reflect.TypeOf((*easyjson.UnknownsUnmarshaler)(nil)).Elem())
@@ -119,6 +136,22 @@ func hasUnknownsMarshaler(t reflect.Type) bool {
 	return t.Implements(reflect.TypeOf((*easyjson.UnknownsMarshaler)(nil)).Elem())
 }
 
+func hasOrderedUnknownsUnmarshaler(t reflect.Type) bool {
+	t = reflect.PtrTo(t)
+	return t.Implements(reflect.TypeOf((*easyjson.OrderedUnknownsUnmarshaler)(nil)).Elem())
+}
+
+func hasOrderedUnknownsMarshaler(t reflect.Type) bool {
+	t = reflect.PtrTo(t)
+	return t.Implements(reflect.TypeOf((*easyjson.OrderedUnknownsMarshaler)(nil)).Elem())
+}
+
+// decodesOrderedUnknowns reports whether the decoder of t passes the unknown
+// fields to it along with the keys preceding them.
+func (g *Generator) decodesOrderedUnknowns(t reflect.Type) bool {
+	return g.orderedUnknowns && !g.disallowUnknownFields && hasOrderedUnknownsUnmarshaler(t)
+}
+
 func hasUnknownsCloner(t reflect.Type) bool {
 	t = reflect.PtrTo(t)
 	method, found := t.MethodByName("Clone")
@@ -137,12 +170,47 @@ func hasUnknownsCloner(t reflect.Type) bool {
 }
 
 func hasUnknownsRecycler(t reflect.Type) bool {
-	t = reflect.PtrTo(t)
-	return t.Implements(reflect.TypeOf((*easyjson.Recycler)(nil)).Elem())
+	return reflect.PtrTo(t).Implements(reflect.TypeOf((*easyjson.Recycler)(nil)).Elem()) && declaresMethod(t, "Recycle")
 }
 func hasUnknownsReturnToPooler(t reflect.Type) bool {
-	t = reflect.PtrTo(t)
-	return t.Implements(reflect.TypeOf((*easyjson.ReturnToPooler)(nil)).Elem())
+	return reflect.PtrTo(t).Implements(reflect.TypeOf((*easyjson.ReturnToPooler)(nil)).Elem()) && declaresMethod(t, "ReturnToPool")
+}
+
+// declaresMethod reports whether the method name of t or *t is declared on t
+// rather than promoted from an embedded field, e.g. Recycle of an embedded
+// easyjson.UnknownFieldsProxy, which recycles only the field. The embedded
+// fields are recycled as fields by the reset of the struct that holds them,
+// when it is pooled. Promoted methods are compiler generated wrappers, as are
+// the methods of *t for value receivers, so both method sets are checked.
+func declaresMethod(t reflect.Type, name string) bool {
+	for _, t := range []reflect.Type{t, reflect.PtrTo(t)} {
+		m, ok := t.MethodByName(name)
+		if !ok {
+			continue
+		}
+		pc := m.Func.Pointer()
+		f := runtime.FuncForPC(pc)
+		if f == nil {
+			return true
+		}
+		if file, _ := f.FileLine(pc); file != "<autogenerated>" {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownsField returns the name of the embedded field of struct t that holds
+// its unknown fields and can be recycled, e.g. an easyjson.UnknownFieldsProxy.
+func unknownsField(t reflect.Type) (string, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && hasUnknownsUnmarshaler(f.Type) &&
+			hasUnknownsRecycler(f.Type) && !hasUnknownsReturnToPooler(f.Type) {
+			return f.Name, true
+		}
+	}
+	return "", false
 }
 
 // genTypeDecoderNoCheck generates decoding code for the type t.
@@ -160,7 +228,7 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 		}
 		fmt.Fprintln(g.out, ws+"}")
 		return nil
-	} else if dec := primitiveStringDecoders[t.Kind()]; dec != "" && tags.asString {
+	} else if dec := primitiveStringDecoders[t.Kind()]; dec != "" && tags.asString && (t.Kind() != reflect.Bool || g.stdStringBools) {
 		if tags.intern && t.Kind() == reflect.String {
 			dec = "in.StringIntern()"
 		}
@@ -350,6 +418,9 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 	case reflect.Map:
 		key := t.Key()
 		keyDec, ok := primitiveStringDecoders[key.Kind()]
+		if key.Kind() == reflect.Bool {
+			keyDec, ok = "", false // As in encoding/json, bools are not valid keys.
+		}
 		if !ok && !hasCustomUnmarshaler(key) {
 			return fmt.Errorf("map type %v not supported: only string and integer keys and types implementing json.Unmarshaler are allowed", key)
 		} // else assume the caller knows what they are doing and that the custom unmarshaler performs the translation from string or integer keys to the key type
@@ -394,6 +465,16 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 			return err
 		}
 
+		if g.disallowDuplicateKeys {
+			data := "string(key)"
+			if key.Kind() != reflect.String {
+				g.imports["fmt"] = "fmt"
+				data = "fmt.Sprint(key)"
+			}
+			fmt.Fprintln(g.out, ws+"    if _, ok := ("+out+")[key]; ok {")
+			g.genDuplicateKeyError(ws+"      ", data)
+			fmt.Fprintln(g.out, ws+"    }")
+		}
 		fmt.Fprintln(g.out, ws+"    ("+out+")[key] = "+tmpVar)
 		fmt.Fprintln(g.out, ws+"    in.WantComma()")
 		fmt.Fprintln(g.out, ws+"  }")
@@ -432,9 +513,11 @@ func (g *Generator) interfaceIsJsonUnmarshaller(t reflect.Type) bool {
 	return t.Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem())
 }
 
-func (g *Generator) genStructFieldDecoder(t reflect.Type, f reflect.StructField) error {
+// genStructFieldDecoder generates the case decoding the field f. seen is the
+// expression testing the bit of the field in the seen fields bitmask.
+func (g *Generator) genStructFieldDecoder(t reflect.Type, f reflect.StructField, seen string, partial bool) error {
 	jsonName := g.fieldNamer.GetJSONFieldName(t, f)
-	tags := parseFieldTags(f)
+	tags := g.parseFieldTags(f)
 
 	if tags.omit {
 		return nil
@@ -442,32 +525,106 @@ func (g *Generator) genStructFieldDecoder(t reflect.Type, f reflect.StructField)
 	if tags.intern && tags.noCopy {
 		return errors.New("Mutually exclusive tags are specified: 'intern' and 'nocopy'")
 	}
+	if tags.invalid != "" {
+		return fmt.Errorf("field %v of %v: invalid tag option %q", f.Name, t, tags.invalid)
+	}
 
 	fmt.Fprintf(g.out, "    case %q:\n", jsonName)
-	if err := g.genTypeDecoder(f.Type, "out."+f.Name, tags, 3); err != nil {
+	if g.disallowDuplicateKeys {
+		fmt.Fprintf(g.out, "      if %s != 0 {\n", seen)
+		g.genDuplicateKeyError("        ", "key")
+		fmt.Fprintln(g.out, "        break")
+		fmt.Fprintln(g.out, "      }")
+	}
+	if tags.versioned() {
+		// Outside of its versions the field is treated as an unknown key.
+		fmt.Fprintf(g.out, "      if !(%s) {\n", tags.versionCheck("in"))
+		g.genUnknownFieldDecoder(t, partial)
+		fmt.Fprintln(g.out, "        break")
+		fmt.Fprintln(g.out, "      }")
+	}
+	sel, ptrs := fieldAccess(t, f)
+	for _, p := range ptrs {
+		// Embedded pointers are set only if one of their fields is present.
+		fmt.Fprintln(g.out, "      if out."+p.sel+" == nil {")
+		if g.ShouldPool(p.typ) {
+			fmt.Fprintln(g.out, "        out."+p.sel+" = "+g.getType(p.typ.Elem())+"FromPool()")
+		} else {
+			fmt.Fprintln(g.out, "        out."+p.sel+" = new("+g.getType(p.typ.Elem())+")")
+		}
+		fmt.Fprintln(g.out, "      }")
+	}
+	if err := g.genTypeDecoder(f.Type, "out."+sel, tags, 3); err != nil {
 		return err
 	}
 
 	if tags.required {
-		fmt.Fprintf(g.out, "%sSet = true\n", f.Name)
+		fmt.Fprintf(g.out, "%sSet = true\n", requiredVar(sel))
 	}
 
 	return nil
 }
 
+// genUnknownFieldDecoder generates code handling a key that does not match any
+// field of the struct t.
+func (g *Generator) genUnknownFieldDecoder(t reflect.Type, partial bool) {
+	if g.disallowDuplicateKeys {
+		fmt.Fprintln(g.out, "      if _, ok := seenKeys[key]; ok {")
+		g.genDuplicateKeyError("        ", "key")
+		fmt.Fprintln(g.out, "        break")
+		fmt.Fprintln(g.out, "      }")
+		fmt.Fprintln(g.out, "      if seenKeys == nil {")
+		fmt.Fprintln(g.out, "        seenKeys = make(map[string]struct{})")
+		fmt.Fprintln(g.out, "      }")
+		fmt.Fprintln(g.out, "      seenKeys[key] = struct{}{}")
+	}
+	if partial {
+		fmt.Fprintln(g.out, "      in.SkipRecursive()")
+	} else if g.disallowUnknownFields {
+		fmt.Fprintln(g.out, `      in.AddError(&jlexer.LexerError{
+          Offset: in.GetPos(),
+          Reason: "unknown field",
+          Data: key,
+      })`)
+	} else if g.decodesOrderedUnknowns(t) {
+		fmt.Fprintln(g.out, "      out.UnmarshalUnknownAfter(in, key, prevKey)")
+	} else if hasUnknownsUnmarshaler(t) {
+		fmt.Fprintln(g.out, "      out.UnmarshalUnknown(in, key)")
+	} else {
+		fmt.Fprintln(g.out, "      in.SkipRecursive()")
+	}
+}
+
+// genDuplicateKeyError generates code reporting the duplicate object key, the
+// string expression data.
+func (g *Generator) genDuplicateKeyError(ws, data string) {
+	fmt.Fprintln(g.out, ws+"in.AddError(&jlexer.LexerError{")
+	fmt.Fprintln(g.out, ws+"  Offset: in.GetPos(),")
+	fmt.Fprintln(g.out, ws+`  Reason: "duplicate key",`)
+	fmt.Fprintln(g.out, ws+"  Data: "+data+",")
+	fmt.Fprintln(g.out, ws+"})")
+}
+
 func (g *Generator) genRequiredFieldSet(t reflect.Type, f reflect.StructField) {
-	tags := parseFieldTags(f)
+	tags := g.parseFieldTags(f)
 
 	if !tags.required {
 		return
 	}
 
-	fmt.Fprintf(g.out, "var %sSet bool\n", f.Name)
+	sel, _ := fieldAccess(t, f)
+	fmt.Fprintf(g.out, "var %sSet bool\n", requiredVar(sel))
+}
+
+// requiredVar returns the name of the variable tracking whether the required
+// field with the selector sel is present.
+func requiredVar(sel string) string {
+	return strings.ReplaceAll(sel, ".", "_")
 }
 
 func (g *Generator) genRequiredFieldCheck(t reflect.Type, f reflect.StructField) {
 	jsonName := g.fieldNamer.GetJSONFieldName(t, f)
-	tags := parseFieldTags(f)
+	tags := g.parseFieldTags(f)
 
 	if !tags.required {
 		return
@@ -475,73 +632,16 @@ func (g *Generator) genRequiredFieldCheck(t reflect.Type, f reflect.StructField)
 
 	g.imports["fmt"] = "fmt"
 
-	fmt.Fprintf(g.out, "if !%sSet {\n", f.Name)
+	sel, _ := fieldAccess(t, f)
+	if tags.versioned() {
+		fmt.Fprintf(g.out, "if !%sSet && (%s) {\n", requiredVar(sel), tags.versionCheck("in"))
+	} else {
+		fmt.Fprintf(g.out, "if !%sSet {\n", requiredVar(sel))
+	}
 	fmt.Fprintf(g.out, "    in.AddError(fmt.Errorf(\"key '%s' is required\"))\n", jsonName)
 	fmt.Fprintf(g.out, "}\n")
 }
 
-func mergeStructFields(fields1, fields2 []reflect.StructField) (fields []reflect.StructField) {
-	used := map[string]bool{}
-	for _, f := range fields2 {
-		used[f.Name] = true
-		fields = append(fields, f)
-	}
-
-	for _, f := range fields1 {
-		if !used[f.Name] {
-			fields = append(fields, f)
-		}
-	}
-	return
-}
-
-func getStructFields(t reflect.Type) ([]reflect.StructField, error) {
-	if t.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("got %v; expected a struct", t)
-	}
-
-	var efields []reflect.StructField
-	var fields []reflect.StructField
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		tags := parseFieldTags(f)
-		if !f.Anonymous || tags.name != "" {
-			continue
-		}
-
-		t1 := f.Type
-		if t1.Kind() == reflect.Ptr {
-			t1 = t1.Elem()
-		}
-
-		if t1.Kind() == reflect.Struct {
-			fs, err := getStructFields(t1)
-			if err != nil {
-				return nil, fmt.Errorf("error processing embedded field: %v", err)
-			}
-			efields = mergeStructFields(efields, fs)
-		} else if (t1.Kind() >= reflect.Bool && t1.Kind() < reflect.Complex128) || t1.Kind() == reflect.String {
-			if strings.Contains(f.Name, ".") || unicode.IsUpper([]rune(f.Name)[0]) {
-				fields = append(fields, f)
-			}
-		}
-	}
-
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		tags := parseFieldTags(f)
-		if f.Anonymous && tags.name == "" {
-			continue
-		}
-
-		c := []rune(f.Name)[0]
-		if unicode.IsUpper(c) {
-			fields = append(fields, f)
-		}
-	}
-	return mergeStructFields(efields, fields), nil
-}
-
 func (g *Generator) genDecoder(t reflect.Type) error {
 	switch t.Kind() {
 	case reflect.Slice, reflect.Array, reflect.Map:
@@ -580,7 +680,18 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 		return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct type", t)
 	}
 
-	fname := g.getDecoderName(t)
+	fs, err := g.getStructFields(t)
+	if err != nil {
+		return fmt.Errorf("cannot generate decoder for %v: %v", t, err)
+	}
+
+	return g.genStructDecoderFunc(g.getDecoderName(t), t, fs, false)
+}
+
+// genStructDecoderFunc generates a decoder function fname for the fields fs of
+// struct t. A partial decoder skips all keys not in fs, including the ones an
+// unknown fields handler or the disallow unknown fields option would process.
+func (g *Generator) genStructDecoderFunc(fname string, t reflect.Type, fs []reflect.StructField, partial bool) error {
 	typ := g.getType(t)
 
 	fmt.Fprintln(g.out, "func "+fname+"(in *jlexer.Lexer, out *"+typ+") {")
@@ -593,28 +704,24 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 	fmt.Fprintln(g.out, "    return")
 	fmt.Fprintln(g.out, "  }")
 
-	// Init embedded pointer fields.
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		if !f.Anonymous || f.Type.Kind() != reflect.Ptr {
-			continue
-		}
-
-		if g.ShouldPool(f.Type) {
-			fmt.Fprintln(g.out, "  out."+f.Name+" = "+g.getType(f.Type.Elem())+"FromPool()")
-		} else {
-			fmt.Fprintln(g.out, "  out."+f.Name+" = new("+g.getType(f.Type.Elem())+")")
-		}
-	}
-
-	fs, err := getStructFields(t)
-	if err != nil {
-		return fmt.Errorf("cannot generate decoder for %v: %v", t, err)
-	}
-
 	for _, f := range fs {
 		g.genRequiredFieldSet(t, f)
 	}
+	if (g.resetMissingFields || g.disallowDuplicateKeys) && len(fs) > 0 {
+		fmt.Fprintf(g.out, "  var seenFields [%d]uint64\n", (len(fs)+63)/64)
+	}
+	if g.disallowDuplicateKeys {
+		fmt.Fprintln(g.out, "  var seenKeys map[string]struct{}")
+	}
+	ordered := !partial && g.decodesOrderedUnknowns(t)
+	if ordered {
+		fmt.Fprintln(g.out, "  var prevKey string")
+	}
+	if g.resetMissingFields && !partial && !g.disallowUnknownFields && hasUnknownsUnmarshaler(t) {
+		if name, ok := unknownsField(t); ok {
+			fmt.Fprintln(g.out, "  out."+name+".Recycle()")
+		}
+	}
 
 	fmt.Fprintln(g.out, "  in.Delim('{')")
 	fmt.Fprintln(g.out, "  for !in.IsDelim('}') {")
@@ -622,25 +729,22 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 	fmt.Fprintln(g.out, "    in.WantColon()")
 
 	fmt.Fprintln(g.out, "    switch key {")
-	for _, f := range fs {
-		if err := g.genStructFieldDecoder(t, f); err != nil {
+	for i, f := range fs {
+		seen := fmt.Sprintf("seenFields[%d]&(1<<%d)", i/64, i%64)
+		if err := g.genStructFieldDecoder(t, f, seen, partial); err != nil {
 			return err
 		}
+		if (g.resetMissingFields || g.disallowDuplicateKeys) && !g.parseFieldTags(f).omit {
+			fmt.Fprintf(g.out, "      seenFields[%d] |= 1 << %d\n", i/64, i%64)
+		}
 	}
 
 	fmt.Fprintln(g.out, "    default:")
-	if g.disallowUnknownFields {
-		fmt.Fprintln(g.out, `      in.AddError(&jlexer.LexerError{
-          Offset: in.GetPos(),
-          Reason: "unknown field",
-          Data: key,
-      })`)
-	} else if hasUnknownsUnmarshaler(t) {
-		fmt.Fprintln(g.out, "      out.UnmarshalUnknown(in, key)")
-	} else {
-		fmt.Fprintln(g.out, "      in.SkipRecursive()")
-	}
+	g.genUnknownFieldDecoder(t, partial)
 	fmt.Fprintln(g.out, "    }")
+	if ordered {
+		fmt.Fprintln(g.out, "    prevKey = key")
+	}
 	fmt.Fprintln(g.out, "    in.WantComma()")
 	fmt.Fprintln(g.out, "  }")
 	fmt.Fprintln(g.out, "  in.Delim('}')")
@@ -651,7 +755,88 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 	for _, f := range fs {
 		g.genRequiredFieldCheck(t, f)
 	}
+	if g.resetMissingFields {
+		for i, f := range fs {
+			if g.parseFieldTags(f).omit {
+				continue
+			}
+			sel, ptrs := fieldAccess(t, f)
+			if len(ptrs) > 0 {
+				fmt.Fprintf(g.out, "  if seenFields[%d]&(1<<%d) == 0 && %s {\n", i/64, i%64, ptrsSet("out", ptrs))
+			} else {
+				fmt.Fprintf(g.out, "  if seenFields[%d]&(1<<%d) == 0 {\n", i/64, i%64)
+			}
+			fmt.Fprintln(g.out, "    out."+sel+" = "+g.zeroValue(f.Type))
+			fmt.Fprintln(g.out, "  }")
+		}
+	}
+
+	fmt.Fprintln(g.out, "}")
+
+	return nil
+}
+
+// zeroValue returns the literal of the zero value of type t.
+func (g *Generator) zeroValue(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Array:
+		return g.getType(t) + "{}"
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return "nil"
+	case reflect.String:
+		return `""`
+	case reflect.Bool:
+		return "false"
+	default:
+		return "0"
+	}
+}
+
+// view is a partial decoder requested for a type.
+type view struct {
+	name   string
+	fields []string
+}
+
+// genStructView generates a partial decoder for the struct t that fills only
+// the fields listed in the view, along with Unmarshal<Name> and
+// UnmarshalEasyJSON<Name> methods using it.
+func (g *Generator) genStructView(t reflect.Type, v view) error {
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot generate view %v for %v, not a struct type", v.name, t)
+	}
+
+	all, err := g.getStructFields(t)
+	if err != nil {
+		return fmt.Errorf("cannot generate view %v for %v: %v", v.name, t, err)
+	}
 
+	var fs []reflect.StructField
+	for _, name := range v.fields {
+		i := slices.IndexFunc(all, func(f reflect.StructField) bool { return f.Name == name })
+		if i < 0 {
+			return fmt.Errorf("cannot generate view %v for %v: no field %v", v.name, t, name)
+		}
+		fs = append(fs, all[i])
+	}
+
+	fname := g.functionName("decode"+v.name, t)
+	if err := g.genStructDecoderFunc(fname, t, fs, true); err != nil {
+		return err
+	}
+
+	typ := g.getType(t)
+
+	fmt.Fprintln(g.out, "// Unmarshal"+v.name+" decodes only the fields of the "+v.name+" view, skipping all other keys")
+	fmt.Fprintln(g.out, "func (v *"+typ+") Unmarshal"+v.name+"(data []byte) error {")
+	fmt.Fprintln(g.out, "  r := jlexer.Lexer{Data: data}")
+	fmt.Fprintln(g.out, "  "+fname+"(&r, v)")
+	fmt.Fprintln(g.out, "  return r.Error()")
+	fmt.Fprintln(g.out, "}")
+
+	fmt.Fprintln(g.out, "// UnmarshalEasyJSON"+v.name+" decodes only the fields of the "+v.name+" view, skipping all other keys")
+	fmt.Fprintln(g.out, "func (v *"+typ+") UnmarshalEasyJSON"+v.name+"(l *jlexer.Lexer) {")
+	fmt.Fprintln(g.out, "  "+fname+"(l, v)")
 	fmt.Fprintln(g.out, "}")
 
 	return nil
@@ -681,6 +866,15 @@ func (g *Generator) genStructUnmarshaler(t reflect.Type) error {
 	fmt.Fprintln(g.out, "  "+fname+"(l, v)")
 	fmt.Fprintln(g.out, "}")
 
+	if g.jsonv2 {
+		g.imports[pkgJSONv2] = "jsonv2"
+		g.imports["encoding/json/jsontext"] = "jsontext"
+		fmt.Fprintln(g.out, "// UnmarshalJSONFrom supports json.UnmarshalerFrom interface of encoding/json/v2")
+		fmt.Fprintln(g.out, "func (v *"+typ+") UnmarshalJSONFrom(dec *jsontext.Decoder) error {")
+		fmt.Fprintln(g.out, "  return jsonv2.UnmarshalFrom(dec, v)")
+		fmt.Fprintln(g.out, "}")
+	}
+
 	return nil
 }
 
@@ -696,7 +890,7 @@ func (g *Generator) genStructPool(out *bytes.Buffer, t reflect.Type) error {
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
 		name := f.Name
-		tags := parseFieldTags(f)
+		tags := g.parseFieldTags(f)
 
 		switch f.Type.Kind() {
 		case reflect.Slice, reflect.Map, reflect.Array:
@@ -808,6 +1002,13 @@ func (g *Generator) genStructPool(out *bytes.Buffer, t reflect.Type) error {
 				fmt.Fprintln(out, `      clear(m.`+name+`[:])`)
 			}
 
+		case reflect.Struct:
+			// Struct values are zeroed with m, but may hold pooled storage,
+			// e.g. easyjson.UnknownFieldsProxy.
+			if !tags.noPool && !g.ShouldPool(f.Type) && !hasUnknownsReturnToPooler(f.Type) && hasUnknownsRecycler(f.Type) {
+				fmt.Fprintln(out, `     m.`+name+`.Recycle()`)
+			}
+
 		case reflect.Ptr:
 			if f.Type.Elem().Kind() == reflect.Struct {
 				if tags.pool && g.marshalerStructs[g.getType(f.Type.Elem())] {
@@ -857,7 +1058,9 @@ func (g *Generator) genStructClone(out *bytes.Buffer, t reflect.Type) error {
 	fmt.Fprintln(out, `  }`)
 	fmt.Fprintln(out)
 
-	if g.ShouldPool(t) || hasUnknownsReturnToPooler(t) || hasUnknownsRecycler(t) {
+	// A Recycle method alone, e.g. promoted from an embedded
+	// easyjson.UnknownFieldsProxy, does not come with a FromPool function.
+	if g.ShouldPool(t) || hasUnknownsReturnToPooler(t) {
 		fmt.Fprintln(out, `   r :=  `+typ+`FromPool()`)
 	} else {
 		fmt.Fprintln(out, `   r :=  &`+typ+`{}`)
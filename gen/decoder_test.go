@@ -0,0 +1,49 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/19910211/easyjson"
+)
+
+type recyclerOwn struct {
+	easyjson.UnknownFieldsProxy
+}
+
+func (*recyclerOwn) Recycle() {}
+
+type recyclerValue struct {
+	easyjson.UnknownFieldsProxy
+}
+
+func (recyclerValue) Recycle() {}
+
+type recyclerEmbedded struct {
+	easyjson.UnknownFieldsProxy
+	A int
+}
+
+type recyclerEmbeddedPtr struct {
+	*easyjson.OrderedUnknownFields
+}
+
+func TestHasUnknownsRecycler(t *testing.T) {
+	for i, test := range []struct {
+		typ  reflect.Type
+		want bool
+	}{
+		{reflect.TypeOf(easyjson.UnknownFieldsProxy{}), true},
+		{reflect.TypeOf(easyjson.OrderedUnknownFields{}), true},
+		// Recycle of an embedded field recycles only the field.
+		{reflect.TypeOf(recyclerEmbedded{}), false},
+		{reflect.TypeOf(recyclerEmbeddedPtr{}), false},
+		{reflect.TypeOf(recyclerOwn{}), true},
+		{reflect.TypeOf(recyclerValue{}), true},
+		{reflect.TypeOf(0), false},
+	} {
+		if got := hasUnknownsRecycler(test.typ); got != test.want {
+			t.Errorf("[%d] hasUnknownsRecycler(%v) = %v; want %v", i, test.typ, got, test.want)
+		}
+	}
+}
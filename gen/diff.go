@@ -0,0 +1,98 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// GenerateDiff instructs to generate DiffEasyJSON methods for the struct types
+// that marshalers were requested for.
+func (g *Generator) GenerateDiff() {
+	g.diff = true
+}
+
+// hasDiff reports whether a DiffEasyJSON method is generated for t.
+func (g *Generator) hasDiff(t reflect.Type) bool {
+	return g.diff && t.Kind() == reflect.Struct && g.marshalers[t]
+}
+
+// genStructDiff generates the DiffEasyJSON method of the struct type t,
+// comparing the members written by the generated encoder one by one.
+func (g *Generator) genStructDiff(t reflect.Type) error {
+	fs, err := g.getStructFields(t)
+	if err != nil {
+		return fmt.Errorf("cannot generate diff for %v: %v", t, err)
+	}
+
+	typ := g.getType(t)
+
+	fmt.Fprintln(g.out, "// DiffEasyJSON appends the members that differ between v and other to changes")
+	fmt.Fprintln(g.out, "func (v *"+typ+") DiffEasyJSON(path string, other *"+typ+", changes []easyjson.Change) []easyjson.Change {")
+	fmt.Fprintln(g.out, "  if v == nil || other == nil {")
+	fmt.Fprintln(g.out, "    return easyjson.DiffValues(changes, path, v, other)")
+	fmt.Fprintln(g.out, "  }")
+
+	for _, f := range fs {
+		if err := g.genStructFieldDiff(t, f); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(g.out, "  return changes")
+	fmt.Fprintln(g.out, "}")
+	return nil
+}
+
+func (g *Generator) genStructFieldDiff(t reflect.Type, f reflect.StructField) error {
+	tags := g.parseFieldTags(f)
+	if tags.omit {
+		return nil
+	}
+
+	jsonName := g.fieldNamer.GetJSONFieldName(t, f)
+	path := fmt.Sprintf("path+%q", "/"+strings.NewReplacer("~", "~0", "/", "~1").Replace(jsonName))
+	noOmitEmpty := (!tags.omitEmpty && !g.omitEmpty) || tags.noOmitEmpty
+	sel, ptrs := fieldAccess(t, f)
+
+	// Members of nil embedded pointers are missing, so they are compared by
+	// DiffField.
+	switch {
+	case len(ptrs) > 0:
+	case g.hasDiff(f.Type):
+		fmt.Fprintln(g.out, "  changes = v."+sel+".DiffEasyJSON("+path+", &other."+sel+", changes)")
+		return nil
+	case f.Type.Kind() == reflect.Ptr && g.hasDiff(f.Type.Elem()):
+		fmt.Fprintln(g.out, "  if v."+sel+" != nil && other."+sel+" != nil {")
+		fmt.Fprintln(g.out, "    changes = v."+sel+".DiffEasyJSON("+path+", other."+sel+", changes)")
+		fmt.Fprintln(g.out, "  } else {")
+		if err := g.genFieldDiffFunc(t, f, tags, path, sel, ptrs, noOmitEmpty); err != nil {
+			return err
+		}
+		fmt.Fprintln(g.out, "  }")
+		return nil
+	}
+	return g.genFieldDiffFunc(t, f, tags, path, sel, ptrs, noOmitEmpty)
+}
+
+// genFieldDiffFunc generates a DiffField call comparing the encodings of the
+// field f of struct t, found at sel behind the embedded pointers ptrs.
+func (g *Generator) genFieldDiffFunc(t reflect.Type, f reflect.StructField, tags fieldTags, path, sel string, ptrs []embeddedPtr, noOmitEmpty bool) error {
+	fmt.Fprintln(g.out, "  changes = easyjson.DiffField(changes, "+path+", func(out *jwriter.Writer, in *"+g.getType(t)+") bool {")
+	if len(ptrs) > 0 {
+		fmt.Fprintln(g.out, "    if !("+ptrsSet("in", ptrs)+") {")
+		fmt.Fprintln(g.out, "      return false")
+		fmt.Fprintln(g.out, "    }")
+	}
+	if !noOmitEmpty {
+		fmt.Fprintln(g.out, "    if !("+g.notEmptyCheck(f.Type, "in."+sel)+") {")
+		fmt.Fprintln(g.out, "      return false")
+		fmt.Fprintln(g.out, "    }")
+	}
+	if err := g.genTypeEncoder(f.Type, "in."+sel, tags, 2, !noOmitEmpty); err != nil {
+		return err
+	}
+	fmt.Fprintln(g.out, "    return true")
+	fmt.Fprintln(g.out, "  }, v, other)")
+	return nil
+}
@@ -34,6 +34,7 @@ var primitiveEncoders = map[reflect.Kind]string{
 
 var primitiveStringEncoders = map[reflect.Kind]string{
 	reflect.String:  "out.String(string(%v))",
+	reflect.Bool:    "out.BoolStr(bool(%v))",
 	reflect.Int:     "out.IntStr(int(%v))",
 	reflect.Int8:    "out.Int8Str(int8(%v))",
 	reflect.Int16:   "out.Int16Str(int16(%v))",
@@ -49,6 +50,10 @@ var primitiveStringEncoders = map[reflect.Kind]string{
 	reflect.Float64: "out.Float64Str(float64(%v))",
 }
 
+var customEncoders = map[string]string{
+	"json.Number": "out.JsonNumber(%v)",
+}
+
 // fieldTags contains parsed version of json struct field tags.
 type fieldTags struct {
 	name string
@@ -65,15 +70,24 @@ type fieldTags struct {
 	NewStruct         bool // 自定义New Struct
 	noPool            bool // 不使用缓存池 Struct
 	pool              bool // 使用缓冲池
+	redact            bool
+	redactHash        bool
+	since             int // first API version the field is present in
+	until             int // last API version the field is present in
+	mergeAppend       bool
+	mergeReplace      bool
+
+	invalid string // malformed option, reported by the generator
 }
 
 // parseFieldTags parses the json field tag into a structure.
-func parseFieldTags(f reflect.StructField) fieldTags {
+func (g *Generator) parseFieldTags(f reflect.StructField) fieldTags {
 	var ret fieldTags
 
-	for i, s := range strings.Split(f.Tag.Get("json"), ",") {
+	tag := f.Tag.Get("json")
+	for i, s := range strings.Split(tag, ",") {
 		switch {
-		case i == 0 && s == "-":
+		case i == 0 && (tag == "-" || s == "-" && !g.stdDashName):
 			ret.omit = true
 		case i == 0:
 			ret.name = s
@@ -99,13 +113,59 @@ func parseFieldTags(f reflect.StructField) fieldTags {
 			ret.noPool = true
 		case s == "pool":
 			ret.pool = true
+		case s == "sensitive" || s == "redact":
+			ret.redact = true
+		case s == "sensitive=hash" || s == "redact=hash":
+			ret.redact = true
+			ret.redactHash = true
+		case s == "merge=append":
+			ret.mergeAppend = true
+		case s == "merge=replace":
+			ret.mergeReplace = true
+		case strings.HasPrefix(s, "merge="):
+			ret.invalid = s
+		case strings.HasPrefix(s, "since="):
+			if v, err := strconv.Atoi(s[len("since="):]); err == nil && v > 0 {
+				ret.since = v
+			} else {
+				ret.invalid = s
+			}
+		case strings.HasPrefix(s, "until="):
+			if v, err := strconv.Atoi(s[len("until="):]); err == nil && v > 0 {
+				ret.until = v
+			} else {
+				ret.invalid = s
+			}
 		}
 
 	}
 
+	if ret.invalid == "" && ret.since > 0 && ret.until > 0 && ret.since > ret.until {
+		ret.invalid = fmt.Sprintf("since=%d,until=%d", ret.since, ret.until)
+	}
+
 	return ret
 }
 
+// versioned reports whether the field is present only in some API versions.
+func (t fieldTags) versioned() bool {
+	return t.since > 0 || t.until > 0
+}
+
+// versionCheck returns the condition under which a versioned field is present,
+// given the name of the writer or lexer holding the version. Version 0 means
+// that no version was requested and selects all fields.
+func (t fieldTags) versionCheck(v string) string {
+	var conds []string
+	if t.since > 0 {
+		conds = append(conds, fmt.Sprintf("%s.Version >= %d", v, t.since))
+	}
+	if t.until > 0 {
+		conds = append(conds, fmt.Sprintf("%s.Version <= %d", v, t.until))
+	}
+	return v + ".Version == 0 || " + strings.Join(conds, " && ")
+}
+
 // genTypeEncoder generates code that encodes in of type t into the writer, but uses marshaler interface if implemented by t.
 func (g *Generator) genTypeEncoder(t reflect.Type, in string, tags fieldTags, indent int, assumeNonEmpty bool) error {
 	ws := strings.Repeat("  ", indent)
@@ -116,6 +176,12 @@ func (g *Generator) genTypeEncoder(t reflect.Type, in string, tags fieldTags, in
 		return nil
 	}
 
+	if g.usesJSONv2(t) && hasMarshalerTo(t) {
+		g.imports[pkgJSONv2] = "jsonv2"
+		fmt.Fprintln(g.out, ws+"jsonv2.Encode(out, &("+in+"))")
+		return nil
+	}
+
 	marshalerIface = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
 	if reflect.PtrTo(t).Implements(marshalerIface) {
 		fmt.Fprintln(g.out, ws+"out.Raw( ("+in+").MarshalJSON() )")
@@ -140,12 +206,23 @@ func hasCustomMarshaler(t reflect.Type) bool {
 		t.Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem())
 }
 
+// hasMarshalerTo reports whether *t implements json.MarshalerTo of
+// encoding/json/v2.
+func hasMarshalerTo(t reflect.Type) bool {
+	return hasJSONv2Method(reflect.PtrTo(t), "MarshalJSONTo", "Encoder")
+}
+
 // genTypeEncoderNoCheck generates code that encodes in of type t into the writer.
 func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldTags, indent int, assumeNonEmpty bool) error {
 	ws := strings.Repeat("  ", indent)
 
 	// Check whether type is primitive, needs to be done after interface check.
-	if enc := primitiveStringEncoders[t.Kind()]; enc != "" && tags.asString {
+	if enc := customEncoders[t.String()]; enc != "" {
+		fmt.Fprintf(g.out, ws+enc+"\n", in)
+		return nil
+	}
+
+	if enc := primitiveStringEncoders[t.Kind()]; enc != "" && tags.asString && (t.Kind() != reflect.Bool || g.stdStringBools) {
 		fmt.Fprintf(g.out, ws+enc+"\n", in)
 		return nil
 	}
@@ -239,6 +316,9 @@ func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldT
 	case reflect.Map:
 		key := t.Key()
 		keyEnc, ok := primitiveStringEncoders[key.Kind()]
+		if key.Kind() == reflect.Bool {
+			keyEnc, ok = "", false // As in encoding/json, bools are not valid keys.
+		}
 		if !ok && !hasCustomMarshaler(key) {
 			return fmt.Errorf("map key type %v not supported: only string and integer keys and types implementing Marshaler interfaces are allowed", key)
 		} // else assume the caller knows what they are doing and that the custom marshaler performs the translation from the key type to a string or integer
@@ -253,7 +333,9 @@ func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldT
 		}
 		fmt.Fprintln(g.out, ws+"  out.RawByte('{')")
 		fmt.Fprintln(g.out, ws+"  "+tmpVar+"First := true")
-		fmt.Fprintln(g.out, ws+"  for "+tmpVar+"Name, "+tmpVar+"Value := range "+in+" {")
+		// The entry is written by a closure so that its code is shared by the
+		// loops over the map and over its sorted keys.
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Entry := func("+tmpVar+"Name "+g.getType(key)+", "+tmpVar+"Value "+g.getType(t.Elem())+") {")
 		fmt.Fprintln(g.out, ws+"    if "+tmpVar+"First { "+tmpVar+"First = false } else { out.RawByte(',') }")
 
 		// NOTE: extra check for TextMarshaler. It overrides default methods.
@@ -273,6 +355,15 @@ func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldT
 			return err
 		}
 
+		fmt.Fprintln(g.out, ws+"  }")
+		fmt.Fprintln(g.out, ws+"  if out.Flags&jwriter.SortMapKeys != 0 {")
+		fmt.Fprintln(g.out, ws+"    for _, "+tmpVar+"Name := range jwriter.SortedMapKeys("+in+") {")
+		fmt.Fprintln(g.out, ws+"      "+tmpVar+"Entry("+tmpVar+"Name, ("+in+")["+tmpVar+"Name])")
+		fmt.Fprintln(g.out, ws+"    }")
+		fmt.Fprintln(g.out, ws+"  } else {")
+		fmt.Fprintln(g.out, ws+"    for "+tmpVar+"Name, "+tmpVar+"Value := range "+in+" {")
+		fmt.Fprintln(g.out, ws+"      "+tmpVar+"Entry("+tmpVar+"Name, "+tmpVar+"Value)")
+		fmt.Fprintln(g.out, ws+"    }")
 		fmt.Fprintln(g.out, ws+"  }")
 		fmt.Fprintln(g.out, ws+"  out.RawByte('}')")
 		fmt.Fprintln(g.out, ws+"}")
@@ -334,35 +425,64 @@ func (g *Generator) notEmptyCheck(t reflect.Type, v string) string {
 
 		return v + " != 0"
 
+	case reflect.Array:
+		// Arrays don't have a useful empty value, but encoding/json treats
+		// zero-length ones as empty.
+		if t.Len() == 0 && g.stdEmptyArrays {
+			return "false"
+		}
+		return "true"
+
 	default:
-		// note: Array types don't have a useful empty value
 		return "true"
 	}
 }
 
 func (g *Generator) genStructFieldEncoder(t reflect.Type, f reflect.StructField, first, firstCondition bool) (bool, error) {
 	jsonName := g.fieldNamer.GetJSONFieldName(t, f)
-	tags := parseFieldTags(f)
+	tags := g.parseFieldTags(f)
 
 	if tags.omit {
 		return firstCondition, nil
 	}
 
+	if tags.invalid != "" {
+		return firstCondition, fmt.Errorf("field %v of %v: invalid tag option %q", f.Name, t, tags.invalid)
+	}
+
 	toggleFirstCondition := firstCondition
+	sel, ptrs := fieldAccess(t, f)
+
+	// Whether the field may be skipped at runtime regardless of its value.
+	conditional := g.fieldMask || tags.versioned() || len(ptrs) > 0
+
+	if len(ptrs) > 0 {
+		// As in encoding/json, fields of nil embedded pointers are skipped.
+		fmt.Fprintln(g.out, "  if", ptrsSet("in", ptrs), "{")
+	}
+	if tags.versioned() {
+		fmt.Fprintln(g.out, "  if", tags.versionCheck("out"), "{")
+	}
+	if g.fieldMask {
+		fmt.Fprintf(g.out, "  if fm, ok := mask.Field(%q); ok {\n", jsonName)
+		fmt.Fprintln(g.out, "  out.Fields = fm")
+	}
 
 	noOmitEmpty := (!tags.omitEmpty && !g.omitEmpty) || tags.noOmitEmpty
 	if noOmitEmpty {
 		fmt.Fprintln(g.out, "  {")
-		toggleFirstCondition = false
+		if !conditional {
+			toggleFirstCondition = false
+		}
 	} else {
-		fmt.Fprintln(g.out, "  if", g.notEmptyCheck(f.Type, "in."+f.Name), "{")
+		fmt.Fprintln(g.out, "  if", g.notEmptyCheck(f.Type, "in."+sel), "{")
 		// can be any in runtime, so toggleFirstCondition stay as is
 	}
 
 	if firstCondition {
 		fmt.Fprintf(g.out, "    const prefix string = %q\n", ","+strconv.Quote(jsonName)+":")
 		if first {
-			if !noOmitEmpty {
+			if !noOmitEmpty || conditional {
 				fmt.Fprintln(g.out, "      first = false")
 			}
 			fmt.Fprintln(g.out, "      out.RawString(prefix[1:])")
@@ -379,13 +499,47 @@ func (g *Generator) genStructFieldEncoder(t reflect.Type, f reflect.StructField,
 		fmt.Fprintln(g.out, "    out.RawString(prefix)")
 	}
 
-	if err := g.genTypeEncoder(f.Type, "in."+f.Name, tags, 2, !noOmitEmpty); err != nil {
+	if tags.redact {
+		if err := g.genRedactedFieldEncoder(f, "in."+sel, tags, noOmitEmpty); err != nil {
+			return toggleFirstCondition, err
+		}
+	} else if err := g.genTypeEncoder(f.Type, "in."+sel, tags, 2, !noOmitEmpty); err != nil {
 		return toggleFirstCondition, err
 	}
 	fmt.Fprintln(g.out, "  }")
+	if g.fieldMask {
+		fmt.Fprintln(g.out, "  }")
+	}
+	if tags.versioned() {
+		fmt.Fprintln(g.out, "  }")
+	}
+	if len(ptrs) > 0 {
+		fmt.Fprintln(g.out, "  }")
+	}
 	return toggleFirstCondition, nil
 }
 
+// genRedactedFieldEncoder generates code writing a placeholder or a hash
+// instead of the value of a sensitive field if the writer has the Redact flag set.
+func (g *Generator) genRedactedFieldEncoder(f reflect.StructField, in string, tags fieldTags, noOmitEmpty bool) error {
+	fmt.Fprintln(g.out, "    if out.Flags&jwriter.Redact != 0 {")
+	if tags.redactHash {
+		fmt.Fprintln(g.out, "      out.RedactedHash(func(out *jwriter.Writer) {")
+		if err := g.genTypeEncoder(f.Type, in, tags, 4, !noOmitEmpty); err != nil {
+			return err
+		}
+		fmt.Fprintln(g.out, "      })")
+	} else {
+		fmt.Fprintln(g.out, "      out.Redacted()")
+	}
+	fmt.Fprintln(g.out, "    } else {")
+	if err := g.genTypeEncoder(f.Type, in, tags, 3, !noOmitEmpty); err != nil {
+		return err
+	}
+	fmt.Fprintln(g.out, "    }")
+	return nil
+}
+
 func (g *Generator) genEncoder(t reflect.Type) error {
 	switch t.Kind() {
 	case reflect.Slice, reflect.Array, reflect.Map:
@@ -426,12 +580,19 @@ func (g *Generator) genStructEncoder(t reflect.Type) error {
 	fmt.Fprintln(g.out, "  out.RawByte('{')")
 	fmt.Fprintln(g.out, "  first := true")
 	fmt.Fprintln(g.out, "  _ = first")
+	if g.fieldMask {
+		fmt.Fprintln(g.out, "  mask := out.Fields")
+	}
 
-	fs, err := getStructFields(t)
+	fs, err := g.getStructFields(t)
 	if err != nil {
 		return fmt.Errorf("cannot generate encoder for %v: %v", t, err)
 	}
 
+	if g.orderedUnknowns && hasOrderedUnknownsMarshaler(t) {
+		return g.genOrderedStructEncoderBody(t, fs)
+	}
+
 	firstCondition := true
 	for i, f := range fs {
 		firstCondition, err = g.genStructFieldEncoder(t, f, i == 0, firstCondition)
@@ -441,12 +602,24 @@ func (g *Generator) genStructEncoder(t reflect.Type) error {
 		}
 	}
 
+	if g.fieldMask {
+		fmt.Fprintln(g.out, "  out.Fields = mask")
+	}
+
 	if hasUnknownsMarshaler(t) {
+		if g.fieldMask {
+			// Unknown fields cannot be matched against the mask, so they are
+			// written only if there is no mask at all.
+			fmt.Fprintln(g.out, "  if mask == nil {")
+		}
 		if !firstCondition {
 			fmt.Fprintln(g.out, "  in.MarshalUnknowns(out, false)")
 		} else {
 			fmt.Fprintln(g.out, "  in.MarshalUnknowns(out, first)")
 		}
+		if g.fieldMask {
+			fmt.Fprintln(g.out, "  }")
+		}
 	}
 
 	fmt.Fprintln(g.out, "  out.RawByte('}')")
@@ -479,5 +652,52 @@ func (g *Generator) genStructMarshaler(t reflect.Type) error {
 	fmt.Fprintln(g.out, "  "+fname+"(w, v)")
 	fmt.Fprintln(g.out, "}")
 
+	if g.jsonv2 {
+		g.imports[pkgJSONv2] = "jsonv2"
+		g.imports["encoding/json/jsontext"] = "jsontext"
+		fmt.Fprintln(g.out, "// MarshalJSONTo supports json.MarshalerTo interface of encoding/json/v2")
+		fmt.Fprintln(g.out, "func (v "+typ+") MarshalJSONTo(enc *jsontext.Encoder) error {")
+		fmt.Fprintln(g.out, "  return jsonv2.MarshalTo(enc, v)")
+		fmt.Fprintln(g.out, "}")
+	}
+
+	return nil
+}
+
+// genOrderedStructEncoderBody generates the rest of the encoder of struct t
+// writing the unknown fields before, between and after the fields fs, in
+// their position in the decoded input.
+func (g *Generator) genOrderedStructEncoderBody(t reflect.Type, fs []reflect.StructField) error {
+	unknownsAfter := func(prev string) {
+		if g.fieldMask {
+			// Unknown fields cannot be matched against the mask, so they are
+			// written only if there is no mask at all.
+			fmt.Fprintf(g.out, "  if mask == nil {\n    first = in.MarshalUnknownsAfter(out, %q, first)\n  }\n", prev)
+		} else {
+			fmt.Fprintf(g.out, "  first = in.MarshalUnknownsAfter(out, %q, first)\n", prev)
+		}
+	}
+
+	unknownsAfter("")
+	for _, f := range fs {
+		// Any field may be the first one written.
+		if _, err := g.genStructFieldEncoder(t, f, false, true); err != nil {
+			return err
+		}
+		unknownsAfter(g.fieldNamer.GetJSONFieldName(t, f))
+	}
+
+	if g.fieldMask {
+		fmt.Fprintln(g.out, "  out.Fields = mask")
+		fmt.Fprintln(g.out, "  if mask == nil {")
+		fmt.Fprintln(g.out, "    in.MarshalUnplacedUnknowns(out, first)")
+		fmt.Fprintln(g.out, "  }")
+	} else {
+		fmt.Fprintln(g.out, "  in.MarshalUnplacedUnknowns(out, first)")
+	}
+
+	fmt.Fprintln(g.out, "  out.RawByte('}')")
+	fmt.Fprintln(g.out, "}")
+
 	return nil
 }
@@ -0,0 +1,205 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// AddEqual requests to generate an Equal method for the type of given object.
+func (g *Generator) AddEqual(obj interface{}) {
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	g.equals[g.getType(t)] = t
+}
+
+// EqualJSONFieldsOnly instructs generated Equal methods to ignore the fields
+// that are not encoded to JSON: unexported fields and fields tagged json:"-".
+func (g *Generator) EqualJSONFieldsOnly() {
+	g.equalJSONOnly = true
+}
+
+func (g *Generator) ShouldEqual(t reflect.Type) bool {
+	_, ok := g.equals[g.getType(t)]
+	return ok
+}
+
+// genStructEqual generates the Equal method of the struct type t. The method
+// reports whether two values are deeply equal, with the same semantics as
+// reflect.DeepEqual.
+func (g *Generator) genStructEqual(out *bytes.Buffer, t reflect.Type) error {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	typ := g.getType(t)
+	fmt.Fprintln(out, "// Equal reports whether m and other hold deeply equal values.")
+	fmt.Fprintln(out, "func (m *"+typ+") Equal(other *"+typ+") bool {")
+	fmt.Fprintln(out, "  if m == other {")
+	fmt.Fprintln(out, "    return true")
+	fmt.Fprintln(out, "  }")
+	fmt.Fprintln(out, "  if m == nil || other == nil {")
+	fmt.Fprintln(out, "    return false")
+	fmt.Fprintln(out, "  }")
+
+	g.genStructFieldsEqual(out, t, "m", "other", map[reflect.Type]bool{t: true})
+
+	fmt.Fprintln(out, "  return true")
+	fmt.Fprintln(out, "}")
+	return nil
+}
+
+// genStructFieldsEqual generates code returning false if the fields of the
+// values a and b of the struct type t differ. With the JSON fields only option
+// the fields of embedded structs without a name are compared one by one, as
+// their exported fields are encoded even if the embedded type is unexported.
+// parents holds the embedded types being compared, to stop at recursive ones.
+func (g *Generator) genStructFieldsEqual(out *bytes.Buffer, t reflect.Type, a, b string, parents map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fa, fb := a+"."+f.Name, b+"."+f.Name
+		if !g.equalJSONOnly {
+			g.genEqualValue(out, f.Type, fa, fb, 0)
+			continue
+		}
+
+		tags := g.parseFieldTags(f)
+		if tags.omit {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && tags.name == "" && ft.Kind() == reflect.Struct {
+			if parents[ft] {
+				continue
+			}
+			parents[ft] = true
+			if f.Type.Kind() == reflect.Ptr {
+				fmt.Fprintln(out, "  if ("+fa+" == nil) != ("+fb+" == nil) {")
+				fmt.Fprintln(out, "    return false")
+				fmt.Fprintln(out, "  }")
+				fmt.Fprintln(out, "  if "+fa+" != nil {")
+				g.genStructFieldsEqual(out, ft, fa, fb, parents)
+				fmt.Fprintln(out, "  }")
+			} else {
+				g.genStructFieldsEqual(out, ft, fa, fb, parents)
+			}
+			delete(parents, ft)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		g.genEqualValue(out, f.Type, fa, fb, 0)
+	}
+}
+
+// genEqualValue generates code returning false if the addressable values a
+// and b of type t differ.
+func (g *Generator) genEqualValue(out *bytes.Buffer, t reflect.Type, a, b string, layer int) {
+	switch t.Kind() {
+	case reflect.Struct:
+		switch {
+		case g.ShouldEqual(t):
+			fmt.Fprintln(out, "  if !"+a+".Equal(&"+b+") {")
+		case safelyComparable(t):
+			fmt.Fprintln(out, "  if "+a+" != "+b+" {")
+		default:
+			g.imports["reflect"] = "reflect"
+			fmt.Fprintln(out, "  if !reflect.DeepEqual("+a+", "+b+") {")
+		}
+		fmt.Fprintln(out, "    return false")
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Struct && g.ShouldEqual(t.Elem()) {
+			fmt.Fprintln(out, "  if !"+a+".Equal("+b+") {")
+			fmt.Fprintln(out, "    return false")
+			fmt.Fprintln(out, "  }")
+			return
+		}
+		fmt.Fprintln(out, "  if ("+a+" == nil) != ("+b+" == nil) {")
+		fmt.Fprintln(out, "    return false")
+		fmt.Fprintln(out, "  }")
+		fmt.Fprintln(out, "  if "+a+" != nil && "+a+" != "+b+" {")
+		g.genEqualValue(out, t.Elem(), "(*"+a+")", "(*"+b+")", layer)
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Slice:
+		i := fmt.Sprintf("i%d", layer)
+		fmt.Fprintln(out, "  if len("+a+") != len("+b+") || ("+a+" == nil) != ("+b+" == nil) {")
+		fmt.Fprintln(out, "    return false")
+		fmt.Fprintln(out, "  }")
+		if t.Elem().Kind() == reflect.Uint8 {
+			fmt.Fprintln(out, "  if string("+a+") != string("+b+") {")
+			fmt.Fprintln(out, "    return false")
+			fmt.Fprintln(out, "  }")
+			return
+		}
+		fmt.Fprintln(out, "  for "+i+" := range "+a+" {")
+		g.genEqualValue(out, t.Elem(), a+"["+i+"]", b+"["+i+"]", layer+1)
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Array:
+		if safelyComparable(t) {
+			fmt.Fprintln(out, "  if "+a+" != "+b+" {")
+			fmt.Fprintln(out, "    return false")
+			fmt.Fprintln(out, "  }")
+			return
+		}
+		i := fmt.Sprintf("i%d", layer)
+		fmt.Fprintln(out, "  for "+i+" := range "+a+" {")
+		g.genEqualValue(out, t.Elem(), a+"["+i+"]", b+"["+i+"]", layer+1)
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Map:
+		k := fmt.Sprintf("k%d", layer)
+		va := fmt.Sprintf("va%d", layer)
+		vb := fmt.Sprintf("vb%d", layer)
+		fmt.Fprintln(out, "  if len("+a+") != len("+b+") || ("+a+" == nil) != ("+b+" == nil) {")
+		fmt.Fprintln(out, "    return false")
+		fmt.Fprintln(out, "  }")
+		fmt.Fprintln(out, "  for "+k+", "+va+" := range "+a+" {")
+		fmt.Fprintln(out, "    "+vb+", ok := "+b+"["+k+"]")
+		fmt.Fprintln(out, "    if !ok {")
+		fmt.Fprintln(out, "      return false")
+		fmt.Fprintln(out, "    }")
+		g.genEqualValue(out, t.Elem(), va, vb, layer+1)
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Interface, reflect.Func:
+		g.imports["reflect"] = "reflect"
+		fmt.Fprintln(out, "  if !reflect.DeepEqual("+a+", "+b+") {")
+		fmt.Fprintln(out, "    return false")
+		fmt.Fprintln(out, "  }")
+
+	default:
+		fmt.Fprintln(out, "  if "+a+" != "+b+" {")
+		fmt.Fprintln(out, "    return false")
+		fmt.Fprintln(out, "  }")
+	}
+}
+
+// safelyComparable reports whether values of type t can be compared with ==
+// without the risk of a runtime panic, i.e. t is comparable and holds no
+// interface values.
+func safelyComparable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Slice, reflect.Map, reflect.Func:
+		return false
+	case reflect.Array:
+		return safelyComparable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !safelyComparable(t.Field(i).Type) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// fieldCandidate is a field found while walking a struct and the structs
+// embedded in it, not yet checked against the other fields with its name.
+type fieldCandidate struct {
+	field  reflect.StructField // Index is the path from the outer struct.
+	name   string
+	tagged bool
+}
+
+// embeddedPtr is an embedded pointer a promoted field is reached through.
+type embeddedPtr struct {
+	sel string
+	typ reflect.Type
+}
+
+// getStructFields returns the fields of the struct t encoded as object members
+// in the order encoding/json writes them, including the ones promoted from
+// embedded structs. The Index of each field is its path from t.
+//
+// The fields are chosen as by encoding/json: of the fields with the same JSON
+// name, the least nested one wins, then the one with the name given by a tag.
+// If that leaves more than one, none of them is encoded. A warning is printed
+// for each field left out.
+func (g *Generator) getStructFields(t reflect.Type) ([]reflect.StructField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("got %v; expected a struct", t)
+	}
+
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var all []fieldCandidate
+	var next []embedded
+	count := map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+	for current := []embedded{{typ: t}}; len(current) > 0; current = next {
+		next = nil
+		nextCount := map[reflect.Type]int{}
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				f := e.typ.Field(i)
+				tags := g.parseFieldTags(f)
+				if tags.omit {
+					continue
+				}
+
+				ft := f.Type
+				if ft.Kind() == reflect.Ptr && ft.Name() == "" {
+					ft = ft.Elem()
+				}
+				f.Index = append(slices.Clone(e.index), i)
+
+				switch {
+				case f.Anonymous && tags.name == "" && ft.Kind() == reflect.Struct:
+					// The fields of embedded structs are promoted, even if the
+					// struct type is unexported.
+					nextCount[ft]++
+					if nextCount[ft] == 1 {
+						next = append(next, embedded{typ: ft, index: f.Index})
+					}
+					continue
+				case f.Anonymous && tags.name == "":
+					if !(ft.Kind() >= reflect.Bool && ft.Kind() < reflect.Complex128) && ft.Kind() != reflect.String {
+						continue
+					}
+					if !strings.Contains(f.Name, ".") && !unicode.IsUpper([]rune(f.Name)[0]) {
+						continue
+					}
+				case !unicode.IsUpper([]rune(f.Name)[0]):
+					continue
+				}
+
+				c := fieldCandidate{field: f, name: g.fieldNamer.GetJSONFieldName(t, f), tagged: tags.name != ""}
+				all = append(all, c)
+				if count[e.typ] > 1 {
+					// The struct is embedded more than once at the same depth,
+					// so all of its fields are ambiguous.
+					all = append(all, c)
+				}
+			}
+		}
+		count = nextCount
+	}
+
+	slices.SortStableFunc(all, func(a, b fieldCandidate) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.field.Index) - len(b.field.Index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.field.Index, b.field.Index)
+	})
+
+	var fields []reflect.StructField
+	for len(all) > 0 {
+		n := 1
+		for n < len(all) && all[n].name == all[0].name {
+			n++
+		}
+		group := all[:n]
+		all = all[n:]
+
+		dominant := group[0]
+		if len(group) > 1 && len(group[1].field.Index) == len(dominant.field.Index) && group[1].tagged == dominant.tagged {
+			var names []string
+			for _, c := range group {
+				if path := fieldPath(t, c.field.Index); !slices.Contains(names, path) {
+					names = append(names, path)
+				}
+			}
+			if len(names) == 1 {
+				g.warnf("field %v of %v is promoted through more than one embedded struct and is not encoded", names[0], t)
+			} else {
+				g.warnf("fields %v of %v have the same JSON name %q and are not encoded", strings.Join(names, ", "), t, dominant.name)
+			}
+			continue
+		}
+
+		fields = append(fields, dominant.field)
+		for _, c := range group[1:] {
+			g.warnf("field %v of %v is hidden by %v with the same JSON name %q",
+				fieldPath(t, c.field.Index), t, fieldPath(t, dominant.field.Index), dominant.name)
+		}
+	}
+
+	slices.SortFunc(fields, func(a, b reflect.StructField) int {
+		return slices.Compare(a.Index, b.Index)
+	})
+	return fields, nil
+}
+
+// fieldPath returns the names of the fields on the path index in the struct t,
+// e.g. "Inner.Name".
+func fieldPath(t reflect.Type, index []int) string {
+	names := make([]string, len(index))
+	for i := range index {
+		names[i] = t.FieldByIndex(index[:i+1]).Name
+	}
+	return strings.Join(names, ".")
+}
+
+// fieldSelector returns the selector of the field at index in the struct t:
+// the name of the field if Go promotes it, otherwise a path through the
+// embedded structs, e.g. "Inner.Name".
+func fieldSelector(t reflect.Type, index []int) string {
+	f := t.FieldByIndex(index)
+	if len(index) == 1 {
+		return f.Name
+	}
+	if sf, ok := t.FieldByName(f.Name); ok && slices.Equal(sf.Index, index) {
+		return f.Name
+	}
+	return fieldSelector(t, index[:len(index)-1]) + "." + f.Name
+}
+
+// fieldAccess returns the selector of the field f returned by getStructFields
+// for the struct t, along with the embedded pointers it is promoted through.
+func fieldAccess(t reflect.Type, f reflect.StructField) (string, []embeddedPtr) {
+	var ptrs []embeddedPtr
+	for i := 1; i < len(f.Index); i++ {
+		if sf := t.FieldByIndex(f.Index[:i]); sf.Type.Kind() == reflect.Ptr {
+			ptrs = append(ptrs, embeddedPtr{sel: fieldSelector(t, f.Index[:i]), typ: sf.Type})
+		}
+	}
+	return fieldSelector(t, f.Index), ptrs
+}
+
+// ptrsSet returns the condition that all the embedded pointers of v are set.
+func ptrsSet(v string, ptrs []embeddedPtr) string {
+	conds := make([]string, len(ptrs))
+	for i, p := range ptrs {
+		conds[i] = v + "." + p.sel + " != nil"
+	}
+	return strings.Join(conds, " && ")
+}
+
+// warnf prints a warning about the types the code is generated for, once.
+func (g *Generator) warnf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if g.warnings[msg] {
+		return
+	}
+	g.warnings[msg] = true
+	fmt.Fprintln(os.Stderr, "easyjson: warning: "+msg)
+}
@@ -0,0 +1,132 @@
+package gen
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type fieldsInner struct {
+	A int
+	B int `json:"b"`
+}
+
+type fieldsOther struct {
+	A int
+	C int
+	fieldsDeep
+}
+
+type fieldsDeep struct {
+	C int
+	D int `json:"b"`
+}
+
+type fieldsPtr struct {
+	E string
+}
+
+type fieldsOuter struct {
+	fieldsInner
+	*fieldsPtr
+	fieldsOther
+	F int `json:"C"`
+	G int `json:"-"`
+	h int
+}
+
+type fieldsTwice struct {
+	fieldsInner
+	Nested struct{ fieldsInner }
+	fieldsTwiceA
+	fieldsTwiceB
+}
+
+type fieldsTwiceA struct{ fieldsPtr }
+
+type fieldsTwiceB struct{ fieldsPtr }
+
+type fieldsCycle struct {
+	*fieldsCycle
+	X int
+}
+
+func TestGetStructFields(t *testing.T) {
+	for _, test := range []struct {
+		typ      reflect.Type
+		fields   []string
+		warnings []string
+	}{
+		{
+			typ: reflect.TypeOf(fieldsOuter{}),
+			// A is ambiguous, B of fieldsInner is shallower than the one of
+			// fieldsDeep and F is shallower than both C.
+			fields: []string{"fieldsInner.B", "fieldsPtr.E", "F"},
+			warnings: []string{
+				`field fieldsOther.C of gen.fieldsOuter is hidden by F with the same JSON name "C"`,
+				`field fieldsOther.fieldsDeep.C of gen.fieldsOuter is hidden by F with the same JSON name "C"`,
+				`field fieldsOther.fieldsDeep.D of gen.fieldsOuter is hidden by fieldsInner.B with the same JSON name "b"`,
+				`fields fieldsInner.A, fieldsOther.A of gen.fieldsOuter have the same JSON name "A" and are not encoded`,
+			},
+		},
+		{
+			typ:    reflect.TypeOf(fieldsTwice{}),
+			fields: []string{"fieldsInner.A", "fieldsInner.B", "Nested"},
+			warnings: []string{
+				`field fieldsTwiceA.fieldsPtr.E of gen.fieldsTwice is promoted through more than one embedded struct and is not encoded`,
+			},
+		},
+		{
+			typ:    reflect.TypeOf(fieldsCycle{}),
+			fields: []string{"X"},
+		},
+	} {
+		g := NewGenerator("fields_test.go")
+		fs, err := g.getStructFields(test.typ)
+		if err != nil {
+			t.Errorf("%v: getStructFields() error: %v", test.typ, err)
+			continue
+		}
+
+		var got []string
+		for _, f := range fs {
+			got = append(got, fieldPath(test.typ, f.Index))
+		}
+		if !reflect.DeepEqual(got, test.fields) {
+			t.Errorf("%v: getStructFields() = %v; want %v", test.typ, got, test.fields)
+		}
+
+		var warnings []string
+		for msg := range g.warnings {
+			warnings = append(warnings, msg)
+		}
+		sort.Strings(warnings)
+		if !reflect.DeepEqual(warnings, test.warnings) {
+			t.Errorf("%v: warnings %q; want %q", test.typ, warnings, test.warnings)
+		}
+	}
+}
+
+func TestFieldAccess(t *testing.T) {
+	typ := reflect.TypeOf(fieldsOuter{})
+	for _, test := range []struct {
+		index []int
+		sel   string
+		ptrs  []string
+	}{
+		{index: []int{0, 0}, sel: "fieldsInner.A"},
+		{index: []int{0, 1}, sel: "B"},
+		{index: []int{1, 0}, sel: "E", ptrs: []string{"fieldsPtr"}},
+		{index: []int{2, 1}, sel: "C"},
+		{index: []int{2, 2, 1}, sel: "D"},
+	} {
+		sel, ptrs := fieldAccess(typ, reflect.StructField{Index: test.index})
+		var gotPtrs []string
+		for _, p := range ptrs {
+			gotPtrs = append(gotPtrs, p.sel)
+		}
+		if sel != test.sel || !reflect.DeepEqual(gotPtrs, test.ptrs) {
+			t.Errorf("fieldAccess(%v) = %v, %v; want %v, %v", test.index, sel, gotPtrs, test.sel, test.ptrs)
+		}
+	}
+}
@@ -3,9 +3,12 @@ package gen
 import (
 	"bytes"
 	"fmt"
+	"go/build"
 	"hash/fnv"
 	"io"
+	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"sort"
@@ -17,6 +20,7 @@ import (
 const pkgWriter = "github.com/19910211/easyjson/jwriter"
 const pkgLexer = "github.com/19910211/easyjson/jlexer"
 const pkgEasyJSON = "github.com/19910211/easyjson"
+const pkgJSONv2 = "github.com/19910211/easyjson/jsonv2"
 
 // FieldNamer defines a policy for generating names for struct fields.
 type FieldNamer interface {
@@ -37,9 +41,19 @@ type Generator struct {
 	noStdMarshalers          bool
 	omitEmpty                bool
 	disallowUnknownFields    bool
+	resetMissingFields       bool
+	disallowDuplicateKeys    bool
+	orderedUnknowns          bool
 	fieldNamer               FieldNamer
 	simpleBytes              bool
 	skipMemberNameUnescaping bool
+	fieldMask                bool
+	schema                   bool
+	diff                     bool
+	jsonv2                   bool
+	stdStringBools           bool
+	stdDashName              bool
+	stdEmptyArrays           bool
 
 	// package path to local alias map for tracking imports
 	imports map[string]string
@@ -52,6 +66,14 @@ type Generator struct {
 
 	clones map[string]reflect.Type
 
+	// types Equal methods were requested for
+	equals        map[string]reflect.Type
+	equalJSONOnly bool
+	merges        map[string]reflect.Type
+
+	// partial decoders requested by user, by type
+	views map[reflect.Type][]view
+
 	// types that encoders were already generated for
 	typesSeen map[reflect.Type]bool
 
@@ -61,6 +83,9 @@ type Generator struct {
 	// function name to relevant type maps to track names of de-/encoders in
 	// case of a name clash or unnamed structs
 	functionNames map[string]reflect.Type
+
+	// warnings already printed
+	warnings map[string]bool
 }
 
 // NewGenerator initializes and returns a Generator.
@@ -77,8 +102,12 @@ func NewGenerator(filename string) *Generator {
 		marshalerStructs: make(map[string]bool),
 		pool:             make(map[string]reflect.Type),
 		clones:           make(map[string]reflect.Type),
+		equals:           make(map[string]reflect.Type),
+		merges:           make(map[string]reflect.Type),
+		views:            make(map[reflect.Type][]view),
 		typesSeen:        make(map[reflect.Type]bool),
 		functionNames:    make(map[string]reflect.Type),
+		warnings:         make(map[string]bool),
 	}
 
 	// Use a file-unique prefix on all auxiliary funcs to avoid
@@ -127,6 +156,25 @@ func (g *Generator) DisallowUnknownFields() {
 	g.disallowUnknownFields = true
 }
 
+// ResetMissingFields instructs decoders to zero the fields that are missing
+// from the input, so decoding into a reused value leaves no stale data.
+func (g *Generator) ResetMissingFields() {
+	g.resetMissingFields = true
+}
+
+// DisallowDuplicateKeys instructs decoders to return an error if an object
+// contains the same key more than once instead of letting the last one win.
+func (g *Generator) DisallowDuplicateKeys() {
+	g.disallowDuplicateKeys = true
+}
+
+// OrderedUnknowns instructs to keep the unknown fields in their position among
+// the known ones for types implementing easyjson.OrderedUnknownsUnmarshaler and
+// easyjson.OrderedUnknownsMarshaler.
+func (g *Generator) OrderedUnknowns() {
+	g.orderedUnknowns = true
+}
+
 // SkipMemberNameUnescaping instructs to skip member names unescaping to improve performance
 func (g *Generator) SkipMemberNameUnescaping() {
 	g.skipMemberNameUnescaping = true
@@ -142,6 +190,42 @@ func (g *Generator) SimpleBytes() {
 	g.simpleBytes = true
 }
 
+// SupportFieldMask instructs to generate struct encoders that write only the
+// members selected by the jwriter.Writer field mask.
+func (g *Generator) SupportFieldMask() {
+	g.fieldMask = true
+}
+
+// GenerateSchema instructs to generate JSONSchema methods returning the JSON
+// Schema of the types that marshalers were requested for.
+func (g *Generator) GenerateSchema() {
+	g.schema = true
+}
+
+// GenerateJSONv2 instructs to generate MarshalJSONTo/UnmarshalJSONFrom methods
+// implementing the interfaces of encoding/json/v2.
+func (g *Generator) GenerateJSONv2() {
+	g.jsonv2 = true
+}
+
+// StdStringBools instructs to apply the ",string" option to bool fields, which
+// are then written as "true" and "false", as encoding/json does.
+func (g *Generator) StdStringBools() {
+	g.stdStringBools = true
+}
+
+// StdDashName instructs to name the fields tagged `json:"-,"` "-", as
+// encoding/json does, rather than to omit them.
+func (g *Generator) StdDashName() {
+	g.stdDashName = true
+}
+
+// StdEmptyArrays instructs to treat zero-length arrays as empty for omitempty,
+// as encoding/json does.
+func (g *Generator) StdEmptyArrays() {
+	g.stdEmptyArrays = true
+}
+
 // addTypes requests to generate encoding/decoding funcs for the given type.
 func (g *Generator) addType(t reflect.Type) {
 	if g.typesSeen[t] {
@@ -184,6 +268,17 @@ func (g *Generator) AddClone(obj interface{}) {
 	g.clones[g.getType(t)] = t
 }
 
+// AddView requests to generate a partial decoder named name for the type of
+// given object. The decoder only fills the listed Go fields and skips all other
+// keys of the input.
+func (g *Generator) AddView(obj interface{}, name string, fields ...string) {
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	g.views[t] = append(g.views[t], view{name: name, fields: fields})
+}
+
 // printHeader prints package declaration and imports.
 func (g *Generator) printHeader() {
 	if g.buildTags != "" {
@@ -227,6 +322,8 @@ func (g *Generator) Run(out io.Writer) error {
 	g.out = &bytes.Buffer{}
 	var poolBuffer = &bytes.Buffer{}
 	var cloneBuffer = &bytes.Buffer{}
+	var equalBuffer = &bytes.Buffer{}
+	var mergeBuffer = &bytes.Buffer{}
 	for len(g.typesUnseen) > 0 {
 		t := g.typesUnseen[len(g.typesUnseen)-1]
 		g.typesUnseen = g.typesUnseen[:len(g.typesUnseen)-1]
@@ -249,6 +346,32 @@ func (g *Generator) Run(out io.Writer) error {
 		if err := g.genStructUnmarshaler(t); err != nil {
 			return err
 		}
+		for _, v := range g.views[t] {
+			if err := g.genStructView(t, v); err != nil {
+				return err
+			}
+		}
+		if g.hasDiff(t) {
+			if err := g.genStructDiff(t); err != nil {
+				return err
+			}
+		}
+	}
+
+	if g.schema {
+		// Schemas refer to all generated types, so they are generated last.
+		var types []reflect.Type
+		for t, ok := range g.marshalers {
+			if ok {
+				types = append(types, t)
+			}
+		}
+		sort.Slice(types, func(i, j int) bool { return types[i].String() < types[j].String() })
+		for _, t := range types {
+			if err := g.genStructSchema(t); err != nil {
+				return err
+			}
+		}
 	}
 
 	for _, t := range g.clones {
@@ -259,6 +382,28 @@ func (g *Generator) Run(out io.Writer) error {
 		}
 	}
 
+	var equalTypes []string
+	for typ := range g.equals {
+		equalTypes = append(equalTypes, typ)
+	}
+	sort.Strings(equalTypes)
+	for _, typ := range equalTypes {
+		if err := g.genStructEqual(equalBuffer, g.equals[typ]); err != nil {
+			return err
+		}
+	}
+
+	var mergeTypes []string
+	for typ := range g.merges {
+		mergeTypes = append(mergeTypes, typ)
+	}
+	sort.Strings(mergeTypes)
+	for _, typ := range mergeTypes {
+		if err := g.genStructMerge(mergeBuffer, g.merges[typ]); err != nil {
+			return err
+		}
+	}
+
 	for _, t := range g.pool {
 		// 生成缓存池
 		g.imports["sync"] = "sync"
@@ -276,7 +421,13 @@ func (g *Generator) Run(out io.Writer) error {
 	if _, err := out.Write(poolBuffer.Bytes()); err != nil {
 		return err
 	}
-	_, err := out.Write(cloneBuffer.Bytes())
+	if _, err := out.Write(cloneBuffer.Bytes()); err != nil {
+		return err
+	}
+	if _, err := out.Write(equalBuffer.Bytes()); err != nil {
+		return err
+	}
+	_, err := out.Write(mergeBuffer.Bytes())
 	return err
 }
 
@@ -753,3 +904,44 @@ func joinFunctionNameParts(keepFirst bool, parts ...string) string {
 	}
 	return buf.String()
 }
+
+// usesJSONv2 reports whether the encoding/json/v2 methods of t, if any, are
+// called by the generated code. They are only with the -jsonv2 option, so that
+// the code builds without GOEXPERIMENT=jsonv2, and never for standard library
+// types such as json.Number, whose v2 methods depend on the toolchain.
+func (g *Generator) usesJSONv2(t reflect.Type) bool {
+	if !g.jsonv2 {
+		return false
+	}
+	return !isStdlibPackage(t.PkgPath())
+}
+
+// isStdlibPackage reports whether the package with the import path is a part
+// of the standard library.
+func isStdlibPackage(path string) bool {
+	if path == "" {
+		return true
+	}
+	if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+		return false
+	}
+	fi, err := os.Stat(filepath.Join(build.Default.GOROOT, "src", filepath.FromSlash(path)))
+	return err == nil && fi.IsDir()
+}
+
+// hasJSONv2Method reports whether t has the method name of the encoding/json/v2
+// MarshalerTo or UnmarshalerFrom interface, taking a *jsontext.Encoder or
+// *jsontext.Decoder as given by arg. The method is matched by its signature so
+// that the generator does not depend on GOEXPERIMENT=jsonv2.
+func hasJSONv2Method(t reflect.Type, name, arg string) bool {
+	m, ok := t.MethodByName(name)
+	if !ok {
+		return false
+	}
+	mt := m.Type
+	if mt.NumIn() != 2 || mt.NumOut() != 1 || mt.Out(0) != reflect.TypeOf((*error)(nil)).Elem() {
+		return false
+	}
+	in := mt.In(1)
+	return in.Kind() == reflect.Ptr && in.Elem().PkgPath() == "encoding/json/jsontext" && in.Elem().Name() == arg
+}
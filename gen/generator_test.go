@@ -85,3 +85,21 @@ func TestFixVendorPath(t *testing.T) {
 	}
 
 }
+
+func TestIsStdlibPackage(t *testing.T) {
+	for i, test := range []struct {
+		In  string
+		Out bool
+	}{
+		{"", true},
+		{"time", true},
+		{"encoding/json", true},
+		{"github.com/19910211/easyjson", false},
+		{"myapp/internal/model", false},
+	} {
+		got := isStdlibPackage(test.In)
+		if got != test.Out {
+			t.Errorf("[%d] isStdlibPackage(%s) = %v; want %v", i, test.In, got, test.Out)
+		}
+	}
+}
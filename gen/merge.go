@@ -0,0 +1,149 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/19910211/easyjson"
+)
+
+// AddMerge requests to generate a Merge method for the type of given object.
+func (g *Generator) AddMerge(obj interface{}) {
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	g.merges[g.getType(t)] = t
+}
+
+// ShouldMerge reports whether a Merge method is generated for t. Unlike
+// getType it does not import the package of t.
+func (g *Generator) ShouldMerge(t reflect.Type) bool {
+	for _, m := range g.merges {
+		if m == t {
+			return true
+		}
+	}
+	return false
+}
+
+// genStructMerge generates the Merge method of the struct type t, overlaying
+// the non-empty exported fields of src onto the receiver.
+func (g *Generator) genStructMerge(out *bytes.Buffer, t reflect.Type) error {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	typ := g.getType(t)
+	fmt.Fprintln(out, "// Merge overlays the non-empty fields of src onto m. Nested structs are merged")
+	fmt.Fprintln(out, "// recursively, maps by key and slices according to their merge tag option.")
+	fmt.Fprintln(out, "func (m *"+typ+") Merge(src *"+typ+") {")
+	fmt.Fprintln(out, "  if m == nil || src == nil {")
+	fmt.Fprintln(out, "    return")
+	fmt.Fprintln(out, "  }")
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tags := g.parseFieldTags(f)
+		if tags.invalid != "" {
+			return fmt.Errorf("field %v of %v: invalid tag option %q", f.Name, t, tags.invalid)
+		}
+		g.genFieldMerge(out, f, tags)
+	}
+
+	fmt.Fprintln(out, "}")
+	return nil
+}
+
+func (g *Generator) genFieldMerge(out *bytes.Buffer, f reflect.StructField, tags fieldTags) {
+	dst, src := "m."+f.Name, "src."+f.Name
+	t := f.Type
+
+	optionalIface := reflect.TypeOf((*easyjson.Optional)(nil)).Elem()
+	if reflect.PtrTo(t).Implements(optionalIface) {
+		fmt.Fprintln(out, "  if "+src+".IsDefined() {")
+		fmt.Fprintln(out, "    "+dst+" = "+src)
+		fmt.Fprintln(out, "  }")
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		switch {
+		case g.ShouldMerge(t):
+			fmt.Fprintln(out, "  "+dst+".Merge(&"+src+")")
+			return
+		case hasIsZero(t):
+			fmt.Fprintln(out, "  if !"+src+".IsZero() {")
+		case safelyComparable(t):
+			fmt.Fprintln(out, "  if "+src+" != ("+g.getType(t)+"{}) {")
+		default:
+			g.imports["reflect"] = "reflect"
+			fmt.Fprintln(out, "  if !reflect.ValueOf("+src+").IsZero() {")
+		}
+		fmt.Fprintln(out, "    "+dst+" = "+src)
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Ptr:
+		fmt.Fprintln(out, "  if "+src+" != nil {")
+		if t.Elem().Kind() == reflect.Struct && g.ShouldMerge(t.Elem()) {
+			// Merging into a new value copies src instead of sharing it.
+			fmt.Fprintln(out, "    if "+dst+" == nil {")
+			fmt.Fprintln(out, "      "+dst+" = new("+g.getType(t.Elem())+")")
+			fmt.Fprintln(out, "    }")
+			fmt.Fprintln(out, "    "+dst+".Merge("+src+")")
+		} else {
+			fmt.Fprintln(out, "    "+dst+" = "+src)
+		}
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Slice:
+		fmt.Fprintln(out, "  if len("+src+") != 0 {")
+		if tags.mergeAppend {
+			fmt.Fprintln(out, "    "+dst+" = append("+dst+", "+src+"...)")
+		} else {
+			fmt.Fprintln(out, "    "+dst+" = append("+src+"[:0:0], "+src+"...)")
+		}
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Map:
+		fmt.Fprintln(out, "  if len("+src+") != 0 {")
+		if tags.mergeReplace {
+			fmt.Fprintln(out, "    "+dst+" = make("+g.getType(t)+", len("+src+"))")
+		} else {
+			fmt.Fprintln(out, "    if "+dst+" == nil {")
+			fmt.Fprintln(out, "      "+dst+" = make("+g.getType(t)+", len("+src+"))")
+			fmt.Fprintln(out, "    }")
+		}
+		fmt.Fprintln(out, "    for k, v := range "+src+" {")
+		fmt.Fprintln(out, "      "+dst+"[k] = v")
+		fmt.Fprintln(out, "    }")
+		fmt.Fprintln(out, "  }")
+
+	case reflect.Array:
+		if safelyComparable(t) {
+			fmt.Fprintln(out, "  if "+src+" != ("+g.getType(t)+"{}) {")
+		} else {
+			g.imports["reflect"] = "reflect"
+			fmt.Fprintln(out, "  if !reflect.ValueOf("+src+").IsZero() {")
+		}
+		fmt.Fprintln(out, "    "+dst+" = "+src)
+		fmt.Fprintln(out, "  }")
+
+	default:
+		fmt.Fprintln(out, "  if "+g.notEmptyCheck(t, src)+" {")
+		fmt.Fprintln(out, "    "+dst+" = "+src)
+		fmt.Fprintln(out, "  }")
+	}
+}
+
+// hasIsZero reports whether t has an IsZero() bool method, like time.Time.
+func hasIsZero(t reflect.Type) bool {
+	m, ok := t.MethodByName("IsZero")
+	return ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 && m.Type.Out(0).Kind() == reflect.Bool
+}
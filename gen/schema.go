@@ -0,0 +1,294 @@
+package gen
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/19910211/easyjson"
+)
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schema is a JSON Schema node. Only the keywords needed to describe the
+// encodings produced by generated code are supported.
+type schema struct {
+	Schema               string       `json:"$schema,omitempty"`
+	Ref                  string       `json:"$ref,omitempty"`
+	Title                string       `json:"title,omitempty"`
+	Type                 interface{}  `json:"type,omitempty"`
+	Format               string       `json:"format,omitempty"`
+	ContentEncoding      string       `json:"contentEncoding,omitempty"`
+	Minimum              *int         `json:"minimum,omitempty"`
+	Items                *schema      `json:"items,omitempty"`
+	MinItems             *int         `json:"minItems,omitempty"`
+	MaxItems             *int         `json:"maxItems,omitempty"`
+	Properties           *schemaProps `json:"properties,omitempty"`
+	Required             []string     `json:"required,omitempty"`
+	AdditionalProperties interface{}  `json:"additionalProperties,omitempty"`
+	AnyOf                []*schema    `json:"anyOf,omitempty"`
+	Defs                 *schemaProps `json:"$defs,omitempty"`
+}
+
+// schemaProps is a list of named schemas encoded as an object, keeping the
+// order in which the names were added.
+type schemaProps struct {
+	names   []string
+	schemas []*schema
+}
+
+func (p *schemaProps) add(name string, s *schema) {
+	p.names = append(p.names, name)
+	p.schemas = append(p.schemas, s)
+}
+
+func (p *schemaProps) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, name := range p.names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Quote(name))
+		b.WriteByte(':')
+		data, err := json.Marshal(p.schemas[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(data)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// schemaBuilder collects the definitions of struct types referenced from the
+// schema of a single type.
+type schemaBuilder struct {
+	g    *Generator
+	root reflect.Type
+
+	defs     schemaProps
+	defNames map[reflect.Type]string
+	used     map[string]bool
+}
+
+// genStructSchema generates a JSONSchema method returning the JSON Schema of
+// the encoding of type t.
+func (g *Generator) genStructSchema(t reflect.Type) error {
+	b := schemaBuilder{
+		g:        g,
+		root:     t,
+		defNames: map[reflect.Type]string{},
+		used:     map[string]bool{},
+	}
+
+	s, err := b.typeSchema(t, fieldTags{})
+	if err != nil {
+		return fmt.Errorf("cannot generate schema for %v: %v", t, err)
+	}
+	s.Schema = schemaDialect
+	s.Title = t.Name()
+	if len(b.defs.names) > 0 {
+		s.Defs = &b.defs
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("cannot generate schema for %v: %v", t, err)
+	}
+
+	lit := "`" + string(data) + "`"
+	if bytes.IndexByte(data, '`') >= 0 {
+		lit = strconv.Quote(string(data))
+	}
+
+	typ := g.getType(t)
+
+	fmt.Fprintln(g.out, "// JSONSchema returns the JSON Schema (draft 2020-12) describing the JSON encoding of "+typ)
+	fmt.Fprintln(g.out, "func (v "+typ+") JSONSchema() []byte {")
+	fmt.Fprintln(g.out, "  return []byte("+lit+")")
+	fmt.Fprintln(g.out, "}")
+	return nil
+}
+
+// typeSchema returns the schema of the values of type t written by generated
+// encoders.
+func (b *schemaBuilder) typeSchema(t reflect.Type, tags fieldTags) (*schema, error) {
+	if t.PkgPath() == "time" && t.Name() == "Time" {
+		return &schema{Type: "string", Format: "date-time"}, nil
+	}
+
+	pt := reflect.PtrTo(t)
+	if generated := b.g.typesSeen[t] || b.g.marshalers[t]; !generated {
+		switch {
+		case pt.Implements(reflect.TypeOf((*easyjson.Marshaler)(nil)).Elem()),
+			pt.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()),
+			b.g.usesJSONv2(t) && hasMarshalerTo(t):
+			// The encoding is not known, any value is accepted.
+			return &schema{}, nil
+		case pt.Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()):
+			return &schema{Type: "string"}, nil
+		}
+	}
+
+	if tags.asString {
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			return &schema{Type: "string"}, nil
+		case reflect.Bool:
+			if b.g.stdStringBools {
+				return &schema{Type: "string"}, nil
+			}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &schema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0
+		return &schema{Type: "integer", Minimum: &zero}, nil
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}, nil
+	case reflect.Interface:
+		return &schema{}, nil
+
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && t.Elem().Name() == "uint8" {
+			if b.g.simpleBytes {
+				return &schema{Type: "string"}, nil
+			}
+			return nullable(&schema{Type: "string", ContentEncoding: "base64"}), nil
+		}
+		items, err := b.typeSchema(t.Elem(), tags)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(&schema{Type: "array", Items: items}), nil
+
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Elem().Name() == "uint8" {
+			if b.g.simpleBytes {
+				return &schema{Type: "string"}, nil
+			}
+			return &schema{Type: "string", ContentEncoding: "base64"}, nil
+		}
+		items, err := b.typeSchema(t.Elem(), tags)
+		if err != nil {
+			return nil, err
+		}
+		n := t.Len()
+		return &schema{Type: "array", Items: items, MinItems: &n, MaxItems: &n}, nil
+
+	case reflect.Map:
+		values, err := b.typeSchema(t.Elem(), tags)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(&schema{Type: "object", AdditionalProperties: values}), nil
+
+	case reflect.Ptr:
+		elem, err := b.typeSchema(t.Elem(), tags)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(elem), nil
+
+	case reflect.Struct:
+		if t == b.root && b.defNames[t] == "" {
+			b.defNames[t] = "#"
+			return b.structSchema(t)
+		}
+		return b.structRef(t)
+	}
+
+	return nil, fmt.Errorf("unsupported type %v", t)
+}
+
+// structRef returns a reference to the definition of the struct type t,
+// adding the definition if needed.
+func (b *schemaBuilder) structRef(t reflect.Type) (*schema, error) {
+	if name := b.defNames[t]; name == "#" {
+		return &schema{Ref: "#"}, nil
+	} else if name != "" {
+		return &schema{Ref: "#/$defs/" + name}, nil
+	}
+
+	base := t.Name()
+	if base == "" {
+		base = "Struct"
+	}
+	name := base
+	for i := 2; b.used[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	b.used[name] = true
+	b.defNames[t] = name
+
+	s, err := b.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	b.defs.add(name, s)
+	return &schema{Ref: "#/$defs/" + name}, nil
+}
+
+// structSchema returns the object schema of the struct type t. Members that
+// are always written are listed as required, along with the ones tagged as
+// required.
+func (b *schemaBuilder) structSchema(t reflect.Type) (*schema, error) {
+	fs, err := b.g.getStructFields(t)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schema{Type: "object", Properties: &schemaProps{}}
+	for _, f := range fs {
+		tags := b.g.parseFieldTags(f)
+		if tags.omit {
+			continue
+		}
+
+		fieldSchema, err := b.typeSchema(f.Type, tags)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %v", f.Name, err)
+		}
+		name := b.g.fieldNamer.GetJSONFieldName(t, f)
+		s.Properties.add(name, fieldSchema)
+
+		_, ptrs := fieldAccess(t, f)
+		alwaysWritten := ((!tags.omitEmpty && !b.g.omitEmpty) || tags.noOmitEmpty) && !tags.versioned() && len(ptrs) == 0
+		if tags.required || alwaysWritten {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	if b.g.disallowUnknownFields {
+		s.AdditionalProperties = false
+	}
+	return s, nil
+}
+
+// nullable allows null in addition to the values matching s.
+func nullable(s *schema) *schema {
+	switch typ := s.Type.(type) {
+	case string:
+		s.Type = []string{typ, "null"}
+		return s
+	case nil:
+		if s.Ref == "" && s.AnyOf == nil {
+			// Already accepts any value.
+			return s
+		}
+	}
+	return &schema{AnyOf: []*schema{s, {Type: "null"}}}
+}
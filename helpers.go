@@ -2,6 +2,7 @@
 package easyjson
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -55,6 +56,22 @@ type UnknownsMarshaler interface {
 	MarshalUnknowns(w *jwriter.Writer, first bool)
 }
 
+// OrderedUnknownsUnmarshaler provides a method to unmarshal unknown struct fields along with the
+// key of the member preceding them, empty for the first one. Used by the code generated with the
+// -ordered_unknowns option.
+type OrderedUnknownsUnmarshaler interface {
+	UnmarshalUnknownAfter(in *jlexer.Lexer, key, prev string)
+}
+
+// OrderedUnknownsMarshaler provides methods to write unknown struct fields among the known ones.
+// Used by the code generated with the -ordered_unknowns option: MarshalUnknownsAfter is called
+// first with an empty prev and then after each known field, whether it is written or not, and
+// returns the new value of first. MarshalUnplacedUnknowns writes the fields with no position.
+type OrderedUnknownsMarshaler interface {
+	MarshalUnknownsAfter(w *jwriter.Writer, prev string, first bool) bool
+	MarshalUnplacedUnknowns(w *jwriter.Writer, first bool)
+}
+
 func isNilInterface(i interface{}) bool {
 	return (*[2]uintptr)(unsafe.Pointer(&i))[1] == 0
 }
@@ -71,6 +88,43 @@ func Marshal(v Marshaler) ([]byte, error) {
 	return w.BuildBytes()
 }
 
+// MarshalFields is like Marshal, but writes only the members selected by mask.
+// The mask is honored by encoders generated with the -field_mask option.
+func MarshalFields(v Marshaler, mask *jwriter.FieldMask) ([]byte, error) {
+	if isNilInterface(v) {
+		return nullBytes, nil
+	}
+
+	w := jwriter.Writer{Fields: mask}
+	v.MarshalEasyJSON(&w)
+	return w.BuildBytes()
+}
+
+// MarshalRedacted is like Marshal, but writes fields tagged as sensitive as a
+// placeholder or a hash. It is meant for logging values that contain PII.
+func MarshalRedacted(v Marshaler) ([]byte, error) {
+	if isNilInterface(v) {
+		return nullBytes, nil
+	}
+
+	w := jwriter.Writer{Flags: jwriter.Redact}
+	v.MarshalEasyJSON(&w)
+	return w.BuildBytes()
+}
+
+// MarshalContext is like Marshal, but makes ctx available to custom marshalers
+// through jwriter.Writer.Context.
+func MarshalContext(ctx context.Context, v Marshaler) ([]byte, error) {
+	if isNilInterface(v) {
+		return nullBytes, nil
+	}
+
+	w := jwriter.Writer{}
+	w.SetContext(ctx)
+	v.MarshalEasyJSON(&w)
+	return w.BuildBytes()
+}
+
 // MarshalToWriter marshals the data to an io.Writer.
 func MarshalToWriter(v Marshaler, w io.Writer) (written int, err error) {
 	if isNilInterface(v) {
@@ -114,6 +168,23 @@ func Unmarshal(data []byte, v Unmarshaler) error {
 	return l.Error()
 }
 
+// UnmarshalContext is like Unmarshal, but makes ctx available to custom
+// unmarshalers through jlexer.Lexer.Context.
+func UnmarshalContext(ctx context.Context, data []byte, v Unmarshaler) error {
+	l := jlexer.Lexer{Data: data}
+	l.SetContext(ctx)
+	v.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
+
+// UnmarshalLimited is like Unmarshal, but returns an error if the input
+// exceeds the limits, e.g. for untrusted input of public endpoints.
+func UnmarshalLimited(data []byte, v Unmarshaler, limits jlexer.Limits) error {
+	l := jlexer.Lexer{Data: data, Limits: limits}
+	v.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
+
 // UnmarshalFromReader reads all the data in the reader and decodes as JSON into the object.
 func UnmarshalFromReader(r io.Reader, v Unmarshaler) error {
 	data, err := ioutil.ReadAll(r)
@@ -0,0 +1,288 @@
+// Package infer generates Go struct definitions from sample JSON documents or
+// from a JSON Schema, as a starting point for types processed by easyjson.
+//
+// Samples are merged: members missing from some of the objects get the
+// omitempty option, members that are sometimes null become pointers, numbers
+// are int64 unless a fraction or exponent was seen, and values of conflicting
+// types become interface{}.
+package infer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/19910211/easyjson/jlexer"
+)
+
+// Options control the generated code.
+type Options struct {
+	// Package is the name used in the package clause, "main" if empty.
+	Package string
+
+	// TypeName is the name of the type of the documents. If empty, the
+	// schema title is used, or "Root".
+	TypeName string
+
+	// EasyJSON adds //easyjson:json comments to the generated types.
+	EasyJSON bool
+}
+
+// FromSamples returns the source of a Go file with types able to hold all of
+// the given JSON documents.
+func FromSamples(samples [][]byte, opts Options) ([]byte, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples")
+	}
+
+	var root *shape
+	for i, data := range samples {
+		l := jlexer.Lexer{Data: data}
+		s := sampleShape(&l)
+		l.Consumed()
+		if err := l.Error(); err != nil {
+			return nil, fmt.Errorf("sample %d: %v", i+1, err)
+		}
+		root = merge(root, s)
+	}
+	return generate(root, opts)
+}
+
+// FromSchema returns the source of a Go file with the types described by a
+// JSON Schema document. Local references ("#/$defs/Name") become separate
+// types; references to objects are held by pointer, so that definitions may
+// be recursive.
+func FromSchema(data []byte, opts Options) ([]byte, error) {
+	l := jlexer.Lexer{Data: data}
+	v := parseValue(&l)
+	l.Consumed()
+	if err := l.Error(); err != nil {
+		return nil, err
+	}
+	o, ok := v.(*object)
+	if !ok {
+		return nil, errors.New("schema is not an object")
+	}
+
+	c := schemaConverter{root: o, defs: map[string]*shape{}}
+	ref, err := c.ref("#")
+	if err != nil {
+		return nil, err
+	}
+	return generate(ref.ref, opts)
+}
+
+// generate writes the types needed to hold values of the root shape.
+func generate(root *shape, opts Options) ([]byte, error) {
+	for root.kind == kindRef {
+		root = root.ref
+	}
+	if opts.Package == "" {
+		opts.Package = "main"
+	}
+	if opts.TypeName == "" {
+		opts.TypeName = root.name
+	}
+	if opts.TypeName == "" {
+		opts.TypeName = "Root"
+	}
+
+	e := emitter{
+		opts:    opts,
+		names:   map[*shape]string{},
+		used:    map[string]bool{},
+		imports: map[string]bool{},
+	}
+
+	rootName := exportedName(opts.TypeName)
+	e.used[rootName] = true
+	if root.kind == kindObject && len(root.fields) > 0 {
+		e.names[root] = rootName
+		e.queue = append(e.queue, root)
+	} else {
+		e.comment()
+		fmt.Fprintf(&e.body, "type %s %s\n\n", rootName, e.goType(root, singular(rootName)))
+	}
+
+	for len(e.queue) > 0 {
+		s := e.queue[0]
+		e.queue = e.queue[1:]
+		e.genStruct(s)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "package %s\n\n", opts.Package)
+	if e.imports["time"] {
+		fmt.Fprintln(&out, `import "time"`)
+		fmt.Fprintln(&out)
+	}
+	out.Write(e.body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// emitter writes struct types, naming each object shape once.
+type emitter struct {
+	opts Options
+	body bytes.Buffer
+
+	names   map[*shape]string
+	used    map[string]bool
+	queue   []*shape
+	imports map[string]bool
+}
+
+func (e *emitter) comment() {
+	if e.opts.EasyJSON {
+		fmt.Fprintln(&e.body, "//easyjson:json")
+	}
+}
+
+// genStruct writes the struct type for the object shape s.
+func (e *emitter) genStruct(s *shape) {
+	name := e.names[s]
+
+	e.comment()
+	fmt.Fprintf(&e.body, "type %s struct {\n", name)
+	fieldNames := map[string]bool{}
+	for _, f := range s.fields {
+		if strings.ContainsAny(f.name, "\"`,\\") {
+			fmt.Fprintf(&e.body, "\t// member %s cannot be represented in a struct tag\n", strconv.Quote(f.name))
+			continue
+		}
+
+		goName := exportedName(f.name)
+		base := goName
+		for i := 2; fieldNames[goName]; i++ {
+			goName = base + strconv.Itoa(i)
+		}
+		fieldNames[goName] = true
+
+		optional := f.count < s.objects
+		tag := f.name
+		switch {
+		case optional:
+			tag += ",omitempty"
+		case tag == "-":
+			// A bare "-" tag would omit the field.
+			tag += ","
+		}
+		fmt.Fprintf(&e.body, "\t%s %s `json:\"%s\"`\n", goName, e.goType(f.shape, goName), tag)
+	}
+	fmt.Fprintf(&e.body, "}\n\n")
+}
+
+// goType returns the Go type for values of shape s. hint is used to name new
+// struct types.
+func (e *emitter) goType(s *shape, hint string) string {
+	ptr := ""
+	if s.nullable {
+		ptr = "*"
+	}
+
+	switch s.kind {
+	case kindBool:
+		return ptr + "bool"
+	case kindInt:
+		return ptr + "int64"
+	case kindFloat:
+		return ptr + "float64"
+	case kindString:
+		return ptr + "string"
+	case kindTime:
+		e.imports["time"] = true
+		return ptr + "time.Time"
+	case kindArray:
+		if s.elem == nil {
+			return "[]interface{}"
+		}
+		return "[]" + e.goType(s.elem, singular(hint))
+	case kindMap:
+		return "map[string]" + e.goType(s.elem, hint+"Value")
+	case kindObject:
+		if len(s.fields) == 0 {
+			return "map[string]interface{}"
+		}
+		return ptr + e.structName(s, hint)
+	case kindRef:
+		t := *s.ref
+		if t.kind == kindObject && len(t.fields) > 0 {
+			// Definitions are shared and may be recursive.
+			return "*" + e.structName(s.ref, hint)
+		}
+		t.nullable = t.nullable || s.nullable
+		return e.goType(&t, hint)
+	}
+	return "interface{}"
+}
+
+// structName returns the name of the struct type for the object shape s,
+// queueing the type to be written if it is new.
+func (e *emitter) structName(s *shape, hint string) string {
+	if name, ok := e.names[s]; ok {
+		return name
+	}
+
+	base := hint
+	if s.name != "" {
+		base = s.name
+	}
+	base = exportedName(base)
+	name := base
+	for i := 2; e.used[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	e.used[name] = true
+	e.names[s] = name
+	e.queue = append(e.queue, s)
+	return name
+}
+
+// commonInitialisms are written in upper case in Go names.
+var commonInitialisms = map[string]bool{
+	"API": true, "ID": true, "IP": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"JSON": true, "SQL": true, "UID": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// exportedName converts a JSON member name, e.g. "user_id" or "first-name",
+// into an exported Go identifier.
+func exportedName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, w := range words {
+		if up := strings.ToUpper(w); commonInitialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	if r := []rune(name)[0]; !unicode.IsLetter(r) {
+		name = "X" + name
+	}
+	return name
+}
+
+// singular returns the name for elements of an array held in a field or type
+// of the given name.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name + "Item"
+}
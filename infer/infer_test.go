@@ -0,0 +1,187 @@
+package infer
+
+import "testing"
+
+func TestFromSamples(t *testing.T) {
+	samples := [][]byte{
+		[]byte(`{"id":1,"user_name":"alice","score":10,"tags":["a"],"owner":{"id":7,"url":"x"},
+			"members":[{"name":"bob","age":30}],"note":null,"meta":{}}`),
+		[]byte(`{"id":2,"user_name":"bob","score":2.5,"tags":[],"owner":null,
+			"members":[{"name":"eve"}],"note":"n","extra":true}`),
+	}
+
+	got, err := FromSamples(samples, Options{Package: "feeds", TypeName: "event", EasyJSON: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `package feeds
+
+//easyjson:json
+type Event struct {
+	ID       int64                  ` + "`json:\"id\"`" + `
+	UserName string                 ` + "`json:\"user_name\"`" + `
+	Score    float64                ` + "`json:\"score\"`" + `
+	Tags     []string               ` + "`json:\"tags\"`" + `
+	Owner    *Owner                 ` + "`json:\"owner\"`" + `
+	Members  []Member               ` + "`json:\"members\"`" + `
+	Note     *string                ` + "`json:\"note\"`" + `
+	Meta     map[string]interface{} ` + "`json:\"meta,omitempty\"`" + `
+	Extra    bool                   ` + "`json:\"extra,omitempty\"`" + `
+}
+
+//easyjson:json
+type Owner struct {
+	ID  int64  ` + "`json:\"id\"`" + `
+	URL string ` + "`json:\"url\"`" + `
+}
+
+//easyjson:json
+type Member struct {
+	Name string ` + "`json:\"name\"`" + `
+	Age  int64  ` + "`json:\"age,omitempty\"`" + `
+}
+`
+	if string(got) != want {
+		t.Errorf("FromSamples() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFromSamplesConflicts(t *testing.T) {
+	got, err := FromSamples([][]byte{[]byte(`[1, "a", null]`), []byte(`[]`)}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "package main\n\ntype Root []interface{}\n"
+	if string(got) != want {
+		t.Errorf("FromSamples() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFromSamplesMembers(t *testing.T) {
+	got, err := FromSamples([][]byte{[]byte(`{"-":1,"big":100000000000000000000,"small":-9223372036854775808}`)}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `package main
+
+type Root struct {
+	Field int64   ` + "`json:\"-,\"`" + `
+	Big   float64 ` + "`json:\"big\"`" + `
+	Small int64   ` + "`json:\"small\"`" + `
+}
+`
+	if string(got) != want {
+		t.Errorf("FromSamples() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFromSamplesErrors(t *testing.T) {
+	if _, err := FromSamples(nil, Options{}); err == nil {
+		t.Error("FromSamples(nil) succeeded; want error")
+	}
+	if _, err := FromSamples([][]byte{[]byte(`{"a":1} x`)}, Options{}); err == nil {
+		t.Error("FromSamples() with trailing data succeeded; want error")
+	}
+}
+
+func TestFromSchema(t *testing.T) {
+	schema := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Order",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"created": {"type": "string", "format": "date-time"},
+			"customer": {"$ref": "#/$defs/Customer"},
+			"lines": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}, "required": ["sku"]}},
+			"labels": {"type": "object", "additionalProperties": {"type": "number"}},
+			"note": {"type": ["string", "null"]},
+			"parent": {"anyOf": [{"$ref": "#"}, {"type": "null"}]}
+		},
+		"required": ["id", "customer", "lines"],
+		"$defs": {
+			"Customer": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"referrer": {"$ref": "#/$defs/Customer"}
+				},
+				"required": ["name"]
+			}
+		}
+	}`
+
+	got, err := FromSchema([]byte(schema), Options{Package: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `package orders
+
+import "time"
+
+type Order struct {
+	ID       int64              ` + "`json:\"id\"`" + `
+	Created  time.Time          ` + "`json:\"created,omitempty\"`" + `
+	Customer *Customer          ` + "`json:\"customer\"`" + `
+	Lines    []Line             ` + "`json:\"lines\"`" + `
+	Labels   map[string]float64 ` + "`json:\"labels,omitempty\"`" + `
+	Note     *string            ` + "`json:\"note,omitempty\"`" + `
+	Parent   *Order             ` + "`json:\"parent,omitempty\"`" + `
+}
+
+type Customer struct {
+	Name     string    ` + "`json:\"name\"`" + `
+	Referrer *Customer ` + "`json:\"referrer,omitempty\"`" + `
+}
+
+type Line struct {
+	Sku string ` + "`json:\"sku\"`" + `
+}
+`
+	if string(got) != want {
+		t.Errorf("FromSchema() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFromSchemaErrors(t *testing.T) {
+	for _, schema := range []string{
+		`[]`,
+		`{"type": "object", "properties": {"a": {"$ref": "#/$defs/Missing"}}}`,
+		`{"type": "object", "properties": {"a": {"$ref": "other.json"}}}`,
+		`{"type": "widget"}`,
+	} {
+		if _, err := FromSchema([]byte(schema), Options{}); err == nil {
+			t.Errorf("FromSchema(%s) succeeded; want error", schema)
+		}
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	for _, test := range []struct{ in, want string }{
+		{"id", "ID"},
+		{"user_id", "UserID"},
+		{"first-name", "FirstName"},
+		{"createdAt", "CreatedAt"},
+		{"2fa", "X2fa"},
+		{"", "Field"},
+		{"@type", "Type"},
+	} {
+		if got := exportedName(test.in); got != test.want {
+			t.Errorf("exportedName(%q) = %q; want %q", test.in, got, test.want)
+		}
+	}
+	for _, test := range []struct{ in, want string }{
+		{"Entries", "Entry"},
+		{"Members", "Member"},
+		{"Address", "AddressItem"},
+		{"Data", "DataItem"},
+	} {
+		if got := singular(test.in); got != test.want {
+			t.Errorf("singular(%q) = %q; want %q", test.in, got, test.want)
+		}
+	}
+}
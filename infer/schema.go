@@ -0,0 +1,236 @@
+package infer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/19910211/easyjson/jlexer"
+)
+
+// object is a parsed JSON object keeping the order of its members.
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *object) get(key string) (interface{}, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+func (o *object) string(key string) string {
+	s, _ := o.values[key].(string)
+	return s
+}
+
+// parseValue reads a single value from the lexer. Objects are returned as
+// *object, everything else as by jlexer.Lexer.Interface.
+func parseValue(l *jlexer.Lexer) interface{} {
+	switch {
+	case l.CurrentToken() != jlexer.TokenDelim:
+		return l.Interface()
+	case l.IsDelim('['):
+		var ret []interface{}
+		l.Delim('[')
+		for !l.IsDelim(']') {
+			ret = append(ret, parseValue(l))
+			l.WantComma()
+		}
+		l.Delim(']')
+		return ret
+	}
+
+	o := &object{values: map[string]interface{}{}}
+	l.Delim('{')
+	for !l.IsDelim('}') {
+		key := l.String()
+		l.WantColon()
+		if _, ok := o.values[key]; !ok {
+			o.keys = append(o.keys, key)
+		}
+		o.values[key] = parseValue(l)
+		l.WantComma()
+	}
+	l.Delim('}')
+	return o
+}
+
+// schemaConverter converts JSON Schema documents into shapes.
+type schemaConverter struct {
+	root *object
+	defs map[string]*shape
+}
+
+// shape converts the schema s. Only the keywords describing the structure of
+// values are taken into account; validation keywords are ignored.
+func (c *schemaConverter) shape(s interface{}) (*shape, error) {
+	o, ok := s.(*object)
+	if !ok {
+		// Boolean schemas accept either anything or nothing.
+		return &shape{kind: kindAny}, nil
+	}
+
+	if ref := o.string("$ref"); ref != "" {
+		return c.ref(ref)
+	}
+
+	var ret *shape
+	for _, key := range []string{"anyOf", "oneOf"} {
+		alts, _ := o.values[key].([]interface{})
+		for _, alt := range alts {
+			sh, err := c.shape(alt)
+			if err != nil {
+				return nil, err
+			}
+			ret = merge(ret, sh)
+		}
+	}
+
+	var types []string
+	switch t := o.values["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+	case nil:
+		if _, ok := o.get("properties"); ok {
+			types = []string{"object"}
+		} else if _, ok := o.get("items"); ok {
+			types = []string{"array"}
+		}
+	}
+
+	for _, t := range types {
+		sh, err := c.typeShape(o, t)
+		if err != nil {
+			return nil, err
+		}
+		ret = merge(ret, sh)
+	}
+
+	if ret == nil {
+		return &shape{kind: kindAny}, nil
+	}
+	if title := o.string("title"); title != "" && ret.name == "" {
+		ret.name = title
+	}
+	return ret, nil
+}
+
+// typeShape converts the schema o restricted to a single JSON type.
+func (c *schemaConverter) typeShape(o *object, t string) (*shape, error) {
+	switch t {
+	case "null":
+		return &shape{kind: kindNull}, nil
+	case "boolean":
+		return &shape{kind: kindBool}, nil
+	case "integer":
+		return &shape{kind: kindInt}, nil
+	case "number":
+		return &shape{kind: kindFloat}, nil
+	case "string":
+		if o.string("format") == "date-time" {
+			return &shape{kind: kindTime}, nil
+		}
+		return &shape{kind: kindString}, nil
+
+	case "array":
+		s := &shape{kind: kindArray}
+		if items, ok := o.get("items"); ok {
+			elem, err := c.shape(items)
+			if err != nil {
+				return nil, err
+			}
+			s.elem = elem
+		} else {
+			s.elem = &shape{kind: kindAny}
+		}
+		return s, nil
+
+	case "object":
+		props, _ := o.values["properties"].(*object)
+		if props == nil || len(props.keys) == 0 {
+			s := &shape{kind: kindMap, elem: &shape{kind: kindAny}}
+			if ap, ok := o.values["additionalProperties"].(*object); ok {
+				elem, err := c.shape(ap)
+				if err != nil {
+					return nil, err
+				}
+				s.elem = elem
+			}
+			return s, nil
+		}
+
+		required := map[string]bool{}
+		list, _ := o.values["required"].([]interface{})
+		for _, v := range list {
+			if name, ok := v.(string); ok {
+				required[name] = true
+			}
+		}
+
+		s := &shape{kind: kindObject, objects: 1}
+		for _, name := range props.keys {
+			fs, err := c.shape(props.values[name])
+			if err != nil {
+				return nil, fmt.Errorf("property %q: %v", name, err)
+			}
+			f := &field{name: name, shape: fs}
+			if required[name] {
+				f.count = 1
+			}
+			s.fields = append(s.fields, f)
+		}
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unknown type %q", t)
+}
+
+// ref returns a shape referring to the definition the local JSON Pointer
+// ref points to, converting the definition on first use.
+func (c *schemaConverter) ref(ref string) (*shape, error) {
+	if target, ok := c.defs[ref]; ok {
+		return &shape{kind: kindRef, ref: target}, nil
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+
+	var s interface{} = c.root
+	name := ""
+	if ptr := ref[1:]; ptr != "" {
+		if ptr[0] != '/' {
+			return nil, fmt.Errorf("unsupported reference %q", ref)
+		}
+		for _, tok := range strings.Split(ptr[1:], "/") {
+			tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+			o, ok := s.(*object)
+			if !ok {
+				return nil, fmt.Errorf("unresolved reference %q", ref)
+			}
+			if s, ok = o.get(tok); !ok {
+				return nil, fmt.Errorf("unresolved reference %q", ref)
+			}
+			name = tok
+		}
+	}
+
+	// The target is registered before being converted, so that recursive
+	// references resolve to it.
+	target := &shape{}
+	c.defs[ref] = target
+	def, err := c.shape(s)
+	if err != nil {
+		return nil, err
+	}
+	*target = *def
+	if target.name == "" {
+		target.name = name
+	}
+	return &shape{kind: kindRef, ref: target}, nil
+}
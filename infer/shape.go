@@ -0,0 +1,161 @@
+package infer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/19910211/easyjson/jlexer"
+)
+
+// kind determines the JSON type of a shape.
+type kind byte
+
+const (
+	kindNull   kind = iota // Only null was seen, the type is unknown.
+	kindBool               // true or false.
+	kindInt                // A number without fraction and exponent.
+	kindFloat              // Any number.
+	kindString             // A string.
+	kindTime               // A date-time string, used for schemas only.
+	kindArray              // An array of elem.
+	kindObject             // An object with known members.
+	kindMap                // An object with arbitrary members of type elem.
+	kindRef                // A reference to the shape of a schema definition.
+	kindAny                // Values of conflicting types.
+)
+
+// shape describes the values seen at a single place in the documents.
+type shape struct {
+	kind     kind
+	nullable bool
+
+	elem   *shape   // Elements of arrays and maps.
+	fields []*field // Members of objects in the order they were first seen.
+
+	// objects is the number of objects merged into the shape; a member present
+	// in fewer of them is optional.
+	objects int
+
+	ref  *shape // Target of a kindRef shape.
+	name string // Type name hint, e.g. a schema title or definition name.
+}
+
+// field is an object member.
+type field struct {
+	name  string
+	shape *shape
+	count int // Number of objects the member was present in.
+}
+
+func (s *shape) field(name string) *field {
+	for _, f := range s.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func isNumber(k kind) bool {
+	return k == kindInt || k == kindFloat
+}
+
+// merge combines the shapes of values seen at the same place. It may modify
+// and return a.
+func merge(a, b *shape) *shape {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	nullable := a.nullable || b.nullable || a.kind == kindNull || b.kind == kindNull
+	switch {
+	case a.kind == kindNull:
+		a = b
+	case b.kind == kindNull:
+	case a.kind == b.kind:
+		switch a.kind {
+		case kindArray, kindMap:
+			a.elem = merge(a.elem, b.elem)
+		case kindObject:
+			mergeFields(a, b)
+		case kindRef:
+			if a.ref != b.ref {
+				a = &shape{kind: kindAny}
+			}
+		}
+	case isNumber(a.kind) && isNumber(b.kind):
+		a.kind = kindFloat
+	default:
+		a = &shape{kind: kindAny}
+	}
+	a.nullable = nullable
+	return a
+}
+
+// mergeFields adds the members of b to the object shape a.
+func mergeFields(a, b *shape) {
+	for _, bf := range b.fields {
+		if f := a.field(bf.name); f != nil {
+			f.shape = merge(f.shape, bf.shape)
+			f.count += bf.count
+		} else {
+			a.fields = append(a.fields, bf)
+		}
+	}
+	a.objects += b.objects
+}
+
+// sampleShape reads a single value from the lexer and returns its shape.
+func sampleShape(l *jlexer.Lexer) *shape {
+	switch l.CurrentToken() {
+	case jlexer.TokenNull:
+		l.Null()
+		return &shape{kind: kindNull}
+	case jlexer.TokenBool:
+		l.Bool()
+		return &shape{kind: kindBool}
+	case jlexer.TokenString:
+		l.Skip()
+		return &shape{kind: kindString}
+	case jlexer.TokenNumber:
+		n := string(l.UnsafeJsonNumber())
+		if strings.ContainsAny(n, ".eE") {
+			return &shape{kind: kindFloat}
+		}
+		if _, err := strconv.ParseInt(n, 10, 64); err != nil {
+			// Integers out of the int64 range only fit a float64.
+			return &shape{kind: kindFloat}
+		}
+		return &shape{kind: kindInt}
+	}
+
+	if l.IsDelim('[') {
+		s := &shape{kind: kindArray}
+		l.Delim('[')
+		for !l.IsDelim(']') {
+			s.elem = merge(s.elem, sampleShape(l))
+			l.WantComma()
+		}
+		l.Delim(']')
+		return s
+	}
+
+	s := &shape{kind: kindObject, objects: 1}
+	l.Delim('{')
+	for !l.IsDelim('}') {
+		key := l.String()
+		l.WantColon()
+		v := sampleShape(l)
+		if f := s.field(key); f != nil {
+			f.shape = merge(f.shape, v)
+		} else {
+			s.fields = append(s.fields, &field{name: key, shape: v, count: 1})
+		}
+		l.WantComma()
+	}
+	l.Delim('}')
+	return s
+}
@@ -0,0 +1,111 @@
+package jlexer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// getters reads a value from the lexer with each of its primitives.
+var getters = map[string]func(l *Lexer){
+	"String":           func(l *Lexer) { _ = l.String() },
+	"StringIntern":     func(l *Lexer) { _ = l.StringIntern() },
+	"UnsafeString":     func(l *Lexer) { _ = l.UnsafeString() },
+	"UnsafeBytes":      func(l *Lexer) { _ = l.UnsafeBytes() },
+	"UnsafeFieldName":  func(l *Lexer) { _ = l.UnsafeFieldName(false) },
+	"Bytes":            func(l *Lexer) { _ = l.Bytes() },
+	"Bool":             func(l *Lexer) { _ = l.Bool() },
+	"Int":              func(l *Lexer) { _ = l.Int() },
+	"Int8":             func(l *Lexer) { _ = l.Int8() },
+	"Int16":            func(l *Lexer) { _ = l.Int16() },
+	"Int32":            func(l *Lexer) { _ = l.Int32() },
+	"Int64":            func(l *Lexer) { _ = l.Int64() },
+	"Uint":             func(l *Lexer) { _ = l.Uint() },
+	"Uint8":            func(l *Lexer) { _ = l.Uint8() },
+	"Uint16":           func(l *Lexer) { _ = l.Uint16() },
+	"Uint32":           func(l *Lexer) { _ = l.Uint32() },
+	"Uint64":           func(l *Lexer) { _ = l.Uint64() },
+	"IntStr":           func(l *Lexer) { _ = l.IntStr() },
+	"Int8Str":          func(l *Lexer) { _ = l.Int8Str() },
+	"Int16Str":         func(l *Lexer) { _ = l.Int16Str() },
+	"Int32Str":         func(l *Lexer) { _ = l.Int32Str() },
+	"Int64Str":         func(l *Lexer) { _ = l.Int64Str() },
+	"UintStr":          func(l *Lexer) { _ = l.UintStr() },
+	"Uint8Str":         func(l *Lexer) { _ = l.Uint8Str() },
+	"Uint16Str":        func(l *Lexer) { _ = l.Uint16Str() },
+	"Uint32Str":        func(l *Lexer) { _ = l.Uint32Str() },
+	"Uint64Str":        func(l *Lexer) { _ = l.Uint64Str() },
+	"UintptrStr":       func(l *Lexer) { _ = l.UintptrStr() },
+	"Float32":          func(l *Lexer) { _ = l.Float32() },
+	"Float64":          func(l *Lexer) { _ = l.Float64() },
+	"Float32Str":       func(l *Lexer) { _ = l.Float32Str() },
+	"Float64Str":       func(l *Lexer) { _ = l.Float64Str() },
+	"JsonNumber":       func(l *Lexer) { _ = l.JsonNumber() },
+	"UnsafeJsonNumber": func(l *Lexer) { _ = l.UnsafeJsonNumber() },
+	"Null":             func(l *Lexer) { l.Null() },
+	"Skip":             func(l *Lexer) { l.Skip() },
+	"SkipRecursive":    func(l *Lexer) { l.SkipRecursive() },
+	"Raw":              func(l *Lexer) { _ = l.Raw() },
+	"Interface":        func(l *Lexer) { _ = l.Interface() },
+	"Delim":            func(l *Lexer) { l.Delim('{'); l.Delim('}') },
+}
+
+// modes are the lexer configurations every getter is fuzzed with.
+var modes = map[string]Lexer{
+	"default":  {},
+	"strict":   {Strict: true},
+	"relaxed":  {Relaxed: true},
+	"limits":   {Limits: Limits{MaxDepth: 16, MaxStringLen: 64, MaxElements: 16, MaxTokens: 256}},
+	"multiple": {UseMultipleErrors: true},
+}
+
+func FuzzLexer(f *testing.F) {
+	for _, seed := range []string{
+		`"simple string"`, `"é😀\n"`, `"\ud800"`, `"QUJD"`,
+		`0`, `-12.5e+3`, `01`, `1.`, `"123"`, `true`, `null`,
+		`{"a":[1,{"b":null}],"c":"d"}`, `[1,2,3]`, `[[[[]]]]`, `{}`,
+		`{a: 'b', /* c */ d: 0x1F,}`, `[1] junk`, "\"\xff\"",
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// No getter may panic, whatever the input and mode.
+		for _, base := range modes {
+			for _, get := range getters {
+				l := base
+				l.Data = data
+				get(&l)
+				l.Consumed()
+				_ = l.Error()
+				_ = l.GetNonFatalErrors()
+			}
+		}
+
+		// Strict mode accepts only what encoding/json accepts, with the same
+		// result.
+		l := Lexer{Data: data, Strict: true}
+		got := l.Interface()
+		l.Consumed()
+		if l.Error() != nil {
+			return
+		}
+		if !json.Valid(data) {
+			t.Fatalf("strict Interface() accepted %q rejected by encoding/json", data)
+		}
+		var want interface{}
+		if err := json.Unmarshal(data, &want); err != nil {
+			t.Fatalf("json.Unmarshal(%q) error: %v", data, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("strict Interface(%q) = %#v; encoding/json: %#v", data, got, want)
+		}
+
+		s := Lexer{Data: data, Strict: true}
+		s.SkipRecursive()
+		s.Consumed()
+		if err := s.Error(); err != nil {
+			t.Fatalf("strict SkipRecursive(%q) error: %v; Interface() ok", data, err)
+		}
+	})
+}
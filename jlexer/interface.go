@@ -0,0 +1,107 @@
+package jlexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// ObjectMember is a member of an OrderedObject.
+type ObjectMember struct {
+	Key   string
+	Value interface{}
+}
+
+// OrderedObject is an object returned by Interface() with the OrderedObjects
+// option: its members in the order of the input, repeated keys included.
+type OrderedObject []ObjectMember
+
+// Get returns the value of the last member with the key, the one a
+// map[string]interface{} would hold.
+func (o OrderedObject) Get(key string) (interface{}, bool) {
+	for i := len(o) - 1; i >= 0; i-- {
+		if o[i].Key == key {
+			return o[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// MarshalJSON implements encoding/json.Marshaler interface, writing the
+// members in order.
+func (o OrderedObject) MarshalJSON() ([]byte, error) {
+	if o == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// interfaceNumber reads the current number token as Interface() returns it.
+func (r *Lexer) interfaceNumber() interface{} {
+	if r.UseInt64 && isIntegerLiteral(r.token.byteValue) {
+		if n, err := strconv.ParseInt(bytesToStr(r.token.byteValue), 10, 64); err == nil {
+			r.consume()
+			return n
+		}
+	}
+	if r.UseNumber {
+		return r.JsonNumber()
+	}
+	return r.Float64()
+}
+
+// isIntegerLiteral reports whether a number literal has no fraction or
+// exponent.
+func isIntegerLiteral(data []byte) bool {
+	if len(data) > 0 && data[0] == '-' {
+		data = data[1:]
+	}
+	if len(data) == 0 {
+		return false
+	}
+	for _, c := range data {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// orderedObject reads the current object as an OrderedObject.
+func (r *Lexer) orderedObject() interface{} {
+	r.consume()
+
+	ret := OrderedObject{}
+	for !r.IsDelim('}') {
+		key := r.String()
+		r.WantColon()
+		ret = append(ret, ObjectMember{Key: key, Value: r.Interface()})
+		r.WantComma()
+	}
+	r.Delim('}')
+
+	if r.Ok() {
+		return ret
+	}
+	return nil
+}
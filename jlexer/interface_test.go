@@ -0,0 +1,80 @@
+package jlexer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestInterfaceOptions(t *testing.T) {
+	for i, test := range []struct {
+		toParse   string
+		lexer     Lexer
+		want      interface{}
+		wantError bool
+	}{
+		{toParse: `5`, lexer: Lexer{UseNumber: true}, want: json.Number("5")},
+		{toParse: `[-1.50e2,12345678901234567890]`, lexer: Lexer{UseNumber: true},
+			want: []interface{}{json.Number("-1.50e2"), json.Number("12345678901234567890")}},
+		{toParse: `1e400`, lexer: Lexer{UseNumber: true}, want: json.Number("1e400")},
+		{toParse: `1e400`, wantError: true},
+
+		{toParse: `[5,-7,0,1.0,1e3,12345678901234567890]`, lexer: Lexer{UseInt64: true},
+			want: []interface{}{int64(5), int64(-7), int64(0), float64(1), float64(1000), float64(12345678901234567890)}},
+		{toParse: `[5,1.0,12345678901234567890]`, lexer: Lexer{UseInt64: true, UseNumber: true},
+			want: []interface{}{int64(5), json.Number("1.0"), json.Number("12345678901234567890")}},
+
+		{toParse: `{}`, lexer: Lexer{OrderedObjects: true}, want: OrderedObject{}},
+		{toParse: `{"b":1,"a":{"y":null,"x":[{}]},"b":2}`, lexer: Lexer{OrderedObjects: true, UseInt64: true},
+			want: OrderedObject{
+				{Key: "b", Value: int64(1)},
+				{Key: "a", Value: OrderedObject{{Key: "y", Value: nil}, {Key: "x", Value: []interface{}{OrderedObject{}}}}},
+				{Key: "b", Value: int64(2)},
+			}},
+		{toParse: `{"a":1,}`, lexer: Lexer{OrderedObjects: true}, wantError: true},
+		{toParse: `{"a" 1}`, lexer: Lexer{OrderedObjects: true}, wantError: true},
+	} {
+		l := test.lexer
+		l.Data = []byte(test.toParse)
+
+		got := l.Interface()
+		l.Consumed()
+		err := l.Error()
+
+		if err != nil && !test.wantError {
+			t.Errorf("[%d, %q] Interface() error: %v", i, test.toParse, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] Interface() ok; want error", i, test.toParse)
+		} else if !test.wantError && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d, %q] Interface() = %#v; want %#v", i, test.toParse, got, test.want)
+		}
+	}
+}
+
+func TestOrderedObject(t *testing.T) {
+	l := Lexer{Data: []byte(`{"z":1,"a":{"<":"b"},"z":[true,1.50]}`), OrderedObjects: true, UseNumber: true}
+	o := l.Interface().(OrderedObject)
+	if err := l.Error(); err != nil {
+		t.Fatalf("Interface() error: %v", err)
+	}
+
+	if v, ok := o.Get("z"); !ok || !reflect.DeepEqual(v, []interface{}{true, json.Number("1.50")}) {
+		t.Errorf(`Get("z") = %v, %v; want [true 1.50], true`, v, ok)
+	}
+	if v, ok := o.Get("missing"); ok {
+		t.Errorf(`Get("missing") = %v, %v; want false`, v, ok)
+	}
+
+	got, err := json.Marshal(o)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	// Keys are escaped as by encoding/json for maps.
+	if want := `{"z":1,"a":{"\u003c":"b"},"z":[true,1.50]}`; string(got) != want {
+		t.Errorf("json.Marshal() = %s; want %s", got, want)
+	}
+
+	if got, err := json.Marshal(OrderedObject(nil)); err != nil || string(got) != "null" {
+		t.Errorf("json.Marshal(nil) = %s, %v; want null, nil", got, err)
+	}
+}
@@ -6,6 +6,7 @@ package jlexer
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -54,10 +55,92 @@ type Lexer struct {
 	UseMultipleErrors bool          // If we want to use multiple errors.
 	fatalError        error         // Fatal error occurred during lexing. It is usually a syntax error.
 	multipleErrors    []*LexerError // Semantic errors occurred during lexing. Marshalling will be continued after finding this errors.
+
+	// Version is the API version to decode. Fields tagged with since=N or
+	// until=N outside of it are treated as unknown keys; 0 decodes all fields.
+	Version int
+
+	// Strict enforces the RFC 8259 grammar exactly: number literals without
+	// leading zeros, strings that are valid UTF-8 without lone surrogates or
+	// raw control characters, and no data after the top-level value. Skipped
+	// values are validated the same way.
+	Strict bool
+
+	// Relaxed accepts the syntax people use in hand-edited files: // and /* */
+	// comments, trailing commas, single-quoted strings, unquoted object keys
+	// and hexadecimal integers. It is ignored in strict mode.
+	Relaxed bool
+	lastSep byte // The last separator consumed, to allow trailing commas.
+
+	// Limits bounds the resources used for decoding, see Limits.
+	Limits Limits
+
+	// UseNumber makes Interface() return numbers as json.Number instead of
+	// float64, like json.Decoder.UseNumber.
+	UseNumber bool
+
+	// UseInt64 makes Interface() return integer literals that fit in int64
+	// as int64. Other numbers are returned as json.Number with UseNumber and
+	// as float64 otherwise.
+	UseInt64 bool
+
+	// OrderedObjects makes Interface() return objects as OrderedObject,
+	// keeping their members in order, instead of map[string]interface{}.
+	OrderedObjects bool
+
+	// Nesting level, number of tokens and element counts of the open arrays
+	// and objects, tracked in strict mode or when limits are set.
+	depth    int
+	tokens   int
+	elements []int
+
+	ctx context.Context // User supplied context, see Context.
+}
+
+// Context returns the context set with SetContext, or context.Background() if
+// there is none. Custom unmarshalers can use it to get request scoped values
+// such as the API version or locale.
+func (r *Lexer) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// SetContext sets the context returned by Context. It is shared by all
+// unmarshalers called with the lexer.
+func (r *Lexer) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// Derive returns a lexer for data, usually a value read from r with Raw, with
+// the options of r: the version, the modes, the limits and the context. The
+// nesting depth of r counts against the MaxDepth limit.
+func (r *Lexer) Derive(data []byte) *Lexer {
+	return &Lexer{
+		Data:              data,
+		UseMultipleErrors: r.UseMultipleErrors,
+		Version:           r.Version,
+		Strict:            r.Strict,
+		Relaxed:           r.Relaxed,
+		Limits:            r.Limits,
+		UseNumber:         r.UseNumber,
+		UseInt64:          r.UseInt64,
+		OrderedObjects:    r.OrderedObjects,
+		depth:             r.depth,
+		ctx:               r.ctx,
+	}
 }
 
 // FetchToken scans the input for the next token.
 func (r *Lexer) FetchToken() {
+	if r.Strict || r.Limits.active() {
+		defer r.afterToken()
+	}
+	r.fetchToken()
+}
+
+func (r *Lexer) fetchToken() {
 	r.token.kind = TokenUndef
 	r.start = r.pos
 
@@ -76,6 +159,10 @@ func (r *Lexer) FetchToken() {
 				r.pos++
 				r.start++
 				r.wantSep = 0
+				r.lastSep = c
+				if c == ',' && r.Limits.MaxElements > 0 {
+					r.countElement()
+				}
 			} else {
 				r.errSyntax()
 			}
@@ -93,6 +180,27 @@ func (r *Lexer) FetchToken() {
 			r.fetchString()
 			return
 
+		case '/':
+			if r.relaxed() && r.skipComment() {
+				r.fetchToken()
+				return
+			}
+			r.errSyntax()
+			return
+
+		case '\'':
+			if !r.relaxed() {
+				r.errSyntax()
+				return
+			}
+			if r.wantSep != 0 {
+				r.errSyntax()
+			}
+
+			r.token.kind = TokenString
+			r.fetchString()
+			return
+
 		case '{', '[':
 			if r.wantSep != 0 {
 				r.errSyntax()
@@ -104,7 +212,8 @@ func (r *Lexer) FetchToken() {
 			return
 
 		case '}', ']':
-			if !r.firstElement && (r.wantSep != ',') {
+			trailingComma := r.wantSep == 0 && r.lastSep == ',' && r.relaxed()
+			if !r.firstElement && (r.wantSep != ',') && !trailingComma {
 				r.errSyntax()
 			}
 			r.wantSep = 0
@@ -117,6 +226,10 @@ func (r *Lexer) FetchToken() {
 			if r.wantSep != 0 {
 				r.errSyntax()
 			}
+			if r.relaxed() && isHexNumber(r.Data[r.pos:]) {
+				r.fetchHexNumber()
+				return
+			}
 			r.token.kind = TokenNumber
 			r.fetchNumber()
 			return
@@ -125,6 +238,9 @@ func (r *Lexer) FetchToken() {
 			if r.wantSep != 0 {
 				r.errSyntax()
 			}
+			if r.relaxed() && r.fetchKey() {
+				return
+			}
 
 			r.token.kind = TokenNull
 			r.fetchNull()
@@ -135,6 +251,10 @@ func (r *Lexer) FetchToken() {
 				r.errSyntax()
 			}
 
+			if r.relaxed() && r.fetchKey() {
+				return
+			}
+
 			r.token.kind = TokenBool
 			r.token.boolValue = true
 			r.fetchTrue()
@@ -145,12 +265,19 @@ func (r *Lexer) FetchToken() {
 				r.errSyntax()
 			}
 
+			if r.relaxed() && r.fetchKey() {
+				return
+			}
+
 			r.token.kind = TokenBool
 			r.token.boolValue = false
 			r.fetchFalse()
 			return
 
 		default:
+			if r.relaxed() && r.wantSep == 0 && r.fetchKey() {
+				return
+			}
 			r.errSyntax()
 			return
 		}
@@ -164,6 +291,12 @@ func isTokenEnd(c byte) bool {
 	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '[' || c == ']' || c == '{' || c == '}' || c == ',' || c == ':'
 }
 
+// tokenEnds is isTokenEnd that also accepts the start of a comment in relaxed
+// mode.
+func (r *Lexer) tokenEnds(c byte) bool {
+	return isTokenEnd(c) || c == '/' && r.relaxed()
+}
+
 // fetchNull fetches and checks remaining bytes of null keyword.
 func (r *Lexer) fetchNull() {
 	r.pos += 4
@@ -171,7 +304,7 @@ func (r *Lexer) fetchNull() {
 		r.Data[r.pos-3] != 'u' ||
 		r.Data[r.pos-2] != 'l' ||
 		r.Data[r.pos-1] != 'l' ||
-		(r.pos != len(r.Data) && !isTokenEnd(r.Data[r.pos])) {
+		(r.pos != len(r.Data) && !r.tokenEnds(r.Data[r.pos])) {
 
 		r.pos -= 4
 		r.errSyntax()
@@ -185,7 +318,7 @@ func (r *Lexer) fetchTrue() {
 		r.Data[r.pos-3] != 'r' ||
 		r.Data[r.pos-2] != 'u' ||
 		r.Data[r.pos-1] != 'e' ||
-		(r.pos != len(r.Data) && !isTokenEnd(r.Data[r.pos])) {
+		(r.pos != len(r.Data) && !r.tokenEnds(r.Data[r.pos])) {
 
 		r.pos -= 4
 		r.errSyntax()
@@ -200,7 +333,7 @@ func (r *Lexer) fetchFalse() {
 		r.Data[r.pos-3] != 'l' ||
 		r.Data[r.pos-2] != 's' ||
 		r.Data[r.pos-1] != 'e' ||
-		(r.pos != len(r.Data) && !isTokenEnd(r.Data[r.pos])) {
+		(r.pos != len(r.Data) && !r.tokenEnds(r.Data[r.pos])) {
 
 		r.pos -= 5
 		r.errSyntax()
@@ -228,7 +361,7 @@ func (r *Lexer) fetchNumber() {
 			afterE = false
 		default:
 			r.pos += i
-			if !isTokenEnd(c) {
+			if !r.tokenEnds(c) {
 				r.errSyntax()
 			} else {
 				r.token.byteValue = r.Data[r.start:r.pos]
@@ -243,9 +376,9 @@ func (r *Lexer) fetchNumber() {
 
 // findStringLen tries to scan into the string literal for ending quote char to determine required size.
 // The size will be exact if no escapes are present and may be inexact if there are escaped chars.
-func findStringLen(data []byte) (isValid bool, length int) {
+func findStringLen(data []byte, quote byte) (isValid bool, length int) {
 	for {
-		idx := bytes.IndexByte(data, '"')
+		idx := bytes.IndexByte(data, quote)
 		if idx == -1 {
 			return false, len(data)
 		}
@@ -280,6 +413,9 @@ func (r *Lexer) unescapeStringToken() (err error) {
 		}
 
 		escapedRune, escapedBytes, err := decodeEscape(data[i:])
+		if err != nil && r.relaxed() && len(data) > i+1 && data[i+1] == '\'' {
+			escapedRune, escapedBytes, err = '\'', 2, nil
+		}
 		if err != nil {
 			r.errParse(err.Error())
 			return err
@@ -373,12 +509,13 @@ func decodeEscape(data []byte) (decoded rune, bytesProcessed int, err error) {
 	return 0, 0, errors.New("incorrectly escaped bytes")
 }
 
-// fetchString scans a string literal token.
+// fetchString scans a string literal token, quoted with the current character.
 func (r *Lexer) fetchString() {
+	quote := r.Data[r.pos]
 	r.pos++
 	data := r.Data[r.pos:]
 
-	isValid, length := findStringLen(data)
+	isValid, length := findStringLen(data, quote)
 	if !isValid {
 		r.pos += length
 		r.errParse("unterminated string literal")
@@ -524,12 +661,19 @@ func (r *Lexer) Skip() {
 // SkipRecursive skips next array or object completely, or just skips a single token if not
 // an array/object.
 //
-// Note: no syntax validation is performed on the skipped data.
+// Note: no syntax validation is performed on the skipped data, unless in
+// strict or relaxed mode or with limits set.
 func (r *Lexer) SkipRecursive() {
 	r.scanToken()
 	var start, end byte
 	startPos := r.start
 
+	if r.Strict || r.relaxed() || r.Limits.active() {
+		r.skipTokens()
+		r.start = startPos
+		return
+	}
+
 	switch r.token.delimValue {
 	case '{':
 		start, end = '{', '}'
@@ -582,8 +726,12 @@ func (r *Lexer) SkipRecursive() {
 	}
 }
 
-// Raw fetches the next item recursively as a data slice
+// Raw fetches the next item recursively as a data slice. In relaxed mode the
+// item is converted to standard JSON, so the slice is newly allocated.
 func (r *Lexer) Raw() []byte {
+	if r.relaxed() {
+		return r.relaxedRaw()
+	}
 	r.SkipRecursive()
 	if !r.Ok() {
 		return nil
@@ -603,6 +751,10 @@ func (r *Lexer) Consumed() {
 	if r.pos > len(r.Data) || !r.Ok() {
 		return
 	}
+	if r.relaxed() {
+		r.skipSpace()
+		r.start = r.pos
+	}
 
 	for _, c := range r.Data[r.pos:] {
 		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
@@ -751,11 +903,15 @@ func (r *Lexer) Bool() bool {
 		r.FetchToken()
 	}
 	if !r.Ok() || r.token.kind != TokenBool {
-		switch r.token.kind {
-		case TokenNull:
+		switch {
+		case r.token.kind == TokenNull:
 			r.consume()
 			return false
-		case TokenNumber:
+		case r.Strict:
+			// encoding/json reads bools only from true and false.
+			r.errInvalidToken("bool")
+			return false
+		case r.token.kind == TokenNumber:
 			v := bytesToStr(r.token.byteValue)
 			if v == "0" {
 				r.consume()
@@ -764,7 +920,7 @@ func (r *Lexer) Bool() bool {
 				r.consume()
 				return true
 			}
-		case TokenString:
+		case r.token.kind == TokenString:
 			v := bytesToStr(r.token.byteValue)
 			if v == "false" {
 				r.consume()
@@ -1093,6 +1249,36 @@ func (r *Lexer) IntStr() int {
 	return int(r.Int64Str())
 }
 
+// BoolStr reads a bool quoted in a string, as written for fields with the
+// ",string" option. Unless in strict mode, an unquoted bool is accepted too, as
+// written by encoders generated before ",string" applied to bools.
+func (r *Lexer) BoolStr() bool {
+	if r.token.kind == TokenUndef && r.Ok() {
+		r.FetchToken()
+	}
+	if r.token.kind == TokenBool && !r.Strict {
+		return r.Bool()
+	}
+
+	s, b := r.unsafeString(false)
+	if !r.Ok() {
+		return false
+	}
+
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	r.addNonfatalError(&LexerError{
+		Offset: r.start,
+		Reason: "invalid bool",
+		Data:   string(b),
+	})
+	return false
+}
+
 func (r *Lexer) Float32() float32 {
 	s := r.number()
 	if !r.Ok() {
@@ -1206,6 +1392,10 @@ func (r *Lexer) JsonNumber() json.Number {
 
 	switch r.token.kind {
 	case TokenString:
+		if r.Strict && !validNumber(r.token.byteValue) {
+			r.errInvalidToken("json.Number")
+			return json.Number("")
+		}
 		return json.Number(r.String())
 	case TokenNumber:
 		return json.Number(r.Raw())
@@ -1229,6 +1419,10 @@ func (r *Lexer) UnsafeJsonNumber() json.Number {
 
 	switch r.token.kind {
 	case TokenString:
+		if r.Strict && !validNumber(r.token.byteValue) {
+			r.errInvalidToken("json.Number")
+			return json.Number("")
+		}
 		return json.Number(r.UnsafeString())
 	case TokenNumber:
 		return json.Number(bytesToStr(r.Raw()))
@@ -1254,7 +1448,7 @@ func (r *Lexer) Interface() interface{} {
 	case TokenString:
 		return r.String()
 	case TokenNumber:
-		return r.Float64()
+		return r.interfaceNumber()
 	case TokenBool:
 		return r.Bool()
 	case TokenNull:
@@ -1262,7 +1456,9 @@ func (r *Lexer) Interface() interface{} {
 		return nil
 	}
 
-	if r.token.delimValue == '{' {
+	if r.token.delimValue == '{' && r.OrderedObjects {
+		return r.orderedObject()
+	} else if r.token.delimValue == '{' {
 		r.consume()
 
 		ret := map[string]interface{}{}
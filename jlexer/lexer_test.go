@@ -175,6 +175,36 @@ func TestBool(t *testing.T) {
 	}
 }
 
+func TestBoolStr(t *testing.T) {
+	for i, test := range []struct {
+		toParse   string
+		want      bool
+		wantError bool
+	}{
+		{toParse: `"true"`, want: true},
+		{toParse: `"false"`, want: false},
+		{toParse: `null`, wantError: true},
+		{toParse: `true`, want: true},
+		{toParse: `false`, want: false},
+		{toParse: `1`, wantError: true},
+		{toParse: `"1"`, wantError: true},
+		{toParse: `"True"`, wantError: true},
+	} {
+		l := Lexer{Data: []byte(test.toParse)}
+
+		got := l.BoolStr()
+		if got != test.want {
+			t.Errorf("[%d, %q] BoolStr() = %v; want %v", i, test.toParse, got, test.want)
+		}
+		err := l.Error()
+		if err != nil && !test.wantError {
+			t.Errorf("[%d, %q] BoolStr() error: %v", i, test.toParse, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] BoolStr() ok; want error", i, test.toParse)
+		}
+	}
+}
+
 func TestSkipRecursive(t *testing.T) {
 	for i, test := range []struct {
 		toParse   string
@@ -388,3 +418,50 @@ func TestCurrentToken(t *testing.T) {
 		l.Skip()
 	}
 }
+
+func TestSeekPointer(t *testing.T) {
+	data := `{"meta":{"request_id":"abc","tags":["x",{"y":1}]},"a/b":1,"m~n":2,"":3,"body":{"huge":[1,2,3]}}`
+	for i, test := range []struct {
+		ptr       string
+		want      string
+		found     bool
+		wantError bool
+	}{
+		{ptr: "", want: data, found: true},
+		{ptr: "/meta/request_id", want: `"abc"`, found: true},
+		{ptr: "/meta/tags/0", want: `"x"`, found: true},
+		{ptr: "/meta/tags/1/y", want: `1`, found: true},
+		{ptr: "/a~1b", want: `1`, found: true},
+		{ptr: "/m~0n", want: `2`, found: true},
+		{ptr: "/", want: `3`, found: true},
+		{ptr: "/body", want: `{"huge":[1,2,3]}`, found: true},
+
+		{ptr: "/missing"},
+		{ptr: "/meta/tags/2"},
+		{ptr: "/meta/tags/01"},
+		{ptr: "/meta/tags/-"},
+		{ptr: "/meta/request_id/x"},
+
+		{ptr: "meta", wantError: true},
+		{ptr: "/m~2n", wantError: true},
+	} {
+		l := Lexer{Data: []byte(data)}
+
+		found := l.SeekPointer(test.ptr)
+		if found != test.found {
+			t.Errorf("[%d, %q] SeekPointer() = %v; want %v", i, test.ptr, found, test.found)
+		}
+		if found {
+			if got := string(l.Raw()); got != test.want {
+				t.Errorf("[%d, %q] Raw() = %v; want %v", i, test.ptr, got, test.want)
+			}
+		}
+
+		err := l.Error()
+		if err != nil && !test.wantError {
+			t.Errorf("[%d, %q] SeekPointer() error: %v", i, test.ptr, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] SeekPointer() ok; want error", i, test.ptr)
+		}
+	}
+}
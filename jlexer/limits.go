@@ -0,0 +1,66 @@
+package jlexer
+
+// Limits bounds the resources spent on decoding untrusted input. Exceeding a
+// limit is reported as an error instead of exhausting the stack or memory.
+// Zero values mean no limit.
+type Limits struct {
+	MaxDepth     int // Maximum nesting depth of arrays and objects.
+	MaxStringLen int // Maximum length of a string literal in the input, in bytes.
+	MaxElements  int // Maximum number of elements of an array or members of an object.
+	MaxTokens    int // Maximum number of tokens in the input.
+}
+
+// active reports whether any limit is set.
+func (l *Limits) active() bool {
+	return *l != Limits{}
+}
+
+// afterToken tracks the nesting of the token just fetched and checks it
+// against the limits and, in strict mode, the grammar.
+func (r *Lexer) afterToken() {
+	if r.fatalError != nil || r.token.kind == TokenUndef {
+		return
+	}
+
+	if r.token.kind == TokenDelim {
+		switch r.token.delimValue {
+		case '{', '[':
+			r.depth++
+			if r.Limits.MaxElements > 0 {
+				r.elements = append(r.elements, 0)
+			}
+		case '}', ']':
+			r.depth--
+			if len(r.elements) > 0 {
+				r.elements = r.elements[:len(r.elements)-1]
+			}
+		}
+	}
+
+	r.tokens++
+	switch {
+	case r.Limits.MaxTokens > 0 && r.tokens > r.Limits.MaxTokens:
+		r.errParse("maximum number of tokens exceeded")
+	case r.Limits.MaxDepth > 0 && r.depth > r.Limits.MaxDepth:
+		r.errParse("maximum nesting depth exceeded")
+	case r.Limits.MaxStringLen > 0 && r.token.kind == TokenString && len(r.token.byteValue) > r.Limits.MaxStringLen:
+		r.errParse("maximum string length exceeded")
+	}
+
+	if r.Strict {
+		r.checkStrictToken()
+	}
+}
+
+// countElement counts a comma separating the elements of the innermost array
+// or object.
+func (r *Lexer) countElement() {
+	if len(r.elements) == 0 {
+		return
+	}
+	n := &r.elements[len(r.elements)-1]
+	*n++
+	if *n+1 > r.Limits.MaxElements {
+		r.errParse("maximum number of elements exceeded")
+	}
+}
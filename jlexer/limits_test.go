@@ -0,0 +1,75 @@
+package jlexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	for i, test := range []struct {
+		toParse   string
+		limits    Limits
+		wantError string
+	}{
+		{toParse: `{"a":[1,{"b":[]}]}`, limits: Limits{MaxDepth: 4, MaxElements: 2, MaxStringLen: 1, MaxTokens: 11}},
+		{toParse: `{"a":[1,{"b":[]}]}`, limits: Limits{MaxDepth: 3}, wantError: "maximum nesting depth exceeded"},
+		{toParse: `[[[[[[[[1]]]]]]]]`, limits: Limits{MaxDepth: 4}, wantError: "maximum nesting depth exceeded"},
+		{toParse: `[1,2,3]`, limits: Limits{MaxElements: 2}, wantError: "maximum number of elements exceeded"},
+		{toParse: `{"a":1,"b":2,"c":3}`, limits: Limits{MaxElements: 2}, wantError: "maximum number of elements exceeded"},
+		{toParse: `[[1,2],[3,4],[5]]`, limits: Limits{MaxElements: 3}},
+		{toParse: `["abc"]`, limits: Limits{MaxStringLen: 2}, wantError: "maximum string length exceeded"},
+		{toParse: `{"long key":1}`, limits: Limits{MaxStringLen: 3}, wantError: "maximum string length exceeded"},
+		{toParse: `{"a":[1,{"b":[]}]}`, limits: Limits{MaxTokens: 10}, wantError: "maximum number of tokens exceeded"},
+	} {
+		for _, skip := range []bool{false, true} {
+			l := Lexer{Data: []byte(test.toParse), Limits: test.limits}
+			if skip {
+				l.SkipRecursive()
+			} else {
+				l.Interface()
+			}
+			l.Consumed()
+
+			err := l.Error()
+			switch {
+			case test.wantError == "" && err != nil:
+				t.Errorf("[%d, %q] skip=%v error: %v", i, test.toParse, skip, err)
+			case test.wantError != "" && (err == nil || !strings.Contains(err.Error(), test.wantError)):
+				t.Errorf("[%d, %q] skip=%v error: %v; want %q", i, test.toParse, skip, err, test.wantError)
+			}
+		}
+	}
+}
+
+func TestDerive(t *testing.T) {
+	l := Lexer{Data: []byte(`{"a":1}`), Strict: true, Limits: Limits{MaxDepth: 3}}
+	l.Delim('{')
+
+	// [[[1]]] is within MaxDepth on its own, but not nested in the object.
+	d := l.Derive([]byte(`[[[1]]]`))
+	if !d.Strict || d.Limits != l.Limits {
+		t.Errorf("Derive() = %+v; want the options of %+v", d, l)
+	}
+	d.Interface()
+	if err := d.Error(); err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Errorf("Interface() error: %v; want maximum nesting depth exceeded", err)
+	}
+}
+
+func TestLimitsDeepNesting(t *testing.T) {
+	data := []byte(strings.Repeat("[", 1000000) + strings.Repeat("]", 1000000))
+
+	l := Lexer{Data: data, Limits: Limits{MaxDepth: 100}}
+	l.Interface()
+	if err := l.Error(); err == nil || !strings.Contains(err.Error(), "maximum nesting depth exceeded") {
+		t.Errorf("Interface() error: %v; want maximum nesting depth exceeded", err)
+	}
+
+	// Skipping does not recurse, so it only takes the token limit.
+	l = Lexer{Data: data, Limits: Limits{MaxTokens: len(data)}}
+	l.SkipRecursive()
+	l.Consumed()
+	if err := l.Error(); err != nil {
+		t.Errorf("SkipRecursive() error: %v", err)
+	}
+}
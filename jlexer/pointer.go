@@ -0,0 +1,136 @@
+package jlexer
+
+import "strings"
+
+// SeekPointer advances the lexer to the value referenced by the JSON Pointer
+// (RFC 6901) ptr, so that the value can be read with Raw() or decoded by an
+// unmarshaler. Members and elements that are not on the path are skipped with
+// SkipRecursive without being decoded.
+//
+// It returns false if the value does not exist or an error occurred; Error()
+// tells the two apart. If an object contains the same key several times, the
+// first occurrence is used.
+func (r *Lexer) SeekPointer(ptr string) bool {
+	if ptr == "" {
+		return r.Ok()
+	}
+	if ptr[0] != '/' {
+		r.AddError(&LexerError{
+			Reason: "invalid JSON pointer",
+			Offset: r.pos,
+			Data:   ptr,
+		})
+		return false
+	}
+
+	for ptr != "" {
+		ptr = ptr[1:]
+		tok := ptr
+		if i := strings.IndexByte(ptr, '/'); i >= 0 {
+			tok, ptr = ptr[:i], ptr[i:]
+		} else {
+			ptr = ""
+		}
+
+		tok, ok := unescapePointerToken(tok)
+		if !ok {
+			r.AddError(&LexerError{
+				Reason: "invalid JSON pointer escape",
+				Offset: r.pos,
+				Data:   tok,
+			})
+			return false
+		}
+		if !r.seekPointerToken(tok) {
+			return false
+		}
+	}
+	return r.Ok()
+}
+
+// seekPointerToken moves into the current object or array to the member or
+// element referenced by a single pointer token.
+func (r *Lexer) seekPointerToken(tok string) bool {
+	if r.CurrentToken() != TokenDelim {
+		return false
+	}
+
+	switch r.token.delimValue {
+	case '{':
+		r.Delim('{')
+		for !r.IsDelim('}') {
+			key := r.UnsafeFieldName(false)
+			r.WantColon()
+			if key == tok {
+				return r.Ok()
+			}
+			r.SkipRecursive()
+			r.WantComma()
+		}
+		r.Delim('}')
+	case '[':
+		idx, ok := pointerIndex(tok)
+		if !ok {
+			return false
+		}
+		r.Delim('[')
+		for i := 0; !r.IsDelim(']'); i++ {
+			if i == idx {
+				return r.Ok()
+			}
+			r.SkipRecursive()
+			r.WantComma()
+		}
+		r.Delim(']')
+	}
+	return false
+}
+
+// unescapePointerToken replaces ~1 with '/' and ~0 with '~'. It returns false
+// if the token contains any other '~' sequence.
+func unescapePointerToken(tok string) (string, bool) {
+	if strings.IndexByte(tok, '~') < 0 {
+		return tok, true
+	}
+
+	var b strings.Builder
+	b.Grow(len(tok))
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		if c != '~' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+1 == len(tok) {
+			return tok, false
+		}
+		i++
+		switch tok[i] {
+		case '0':
+			b.WriteByte('~')
+		case '1':
+			b.WriteByte('/')
+		default:
+			return tok, false
+		}
+	}
+	return b.String(), true
+}
+
+// pointerIndex parses an array index token: a decimal number without leading
+// zeros.
+func pointerIndex(tok string) (int, bool) {
+	if tok == "" || len(tok) > 9 || (len(tok) > 1 && tok[0] == '0') {
+		return 0, false
+	}
+
+	n := 0
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
@@ -0,0 +1,163 @@
+package jlexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// relaxed reports whether the relaxed syntax is accepted. Strict mode takes
+// precedence over it.
+func (r *Lexer) relaxed() bool {
+	return r.Relaxed && !r.Strict
+}
+
+// relaxedRaw returns the next value converted to standard JSON, without
+// comments, trailing commas, single quotes, unquoted keys and hexadecimal
+// numbers, so it can be passed on to json.Unmarshaler and kept in raw messages.
+// Objects keep the order and duplicates of their members.
+func (r *Lexer) relaxedRaw() []byte {
+	if r.token.kind == TokenUndef && r.Ok() {
+		r.FetchToken()
+	}
+	if !r.Ok() {
+		return nil
+	}
+	if r.token.kind == TokenNumber {
+		// The value of hexadecimal numbers is already in decimal.
+		v := append([]byte(nil), r.token.byteValue...)
+		r.consume()
+		return v
+	}
+
+	useNumber, useInt64, ordered := r.UseNumber, r.UseInt64, r.OrderedObjects
+	r.UseNumber, r.UseInt64, r.OrderedObjects = true, false, true
+	v := r.Interface()
+	r.UseNumber, r.UseInt64, r.OrderedObjects = useNumber, useInt64, ordered
+	if !r.Ok() {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		r.AddError(err)
+		return nil
+	}
+	return data
+}
+
+// skipComment skips a // or /* */ comment starting at the current position
+// and reports whether there was one.
+func (r *Lexer) skipComment() bool {
+	data := r.Data[r.pos:]
+	if len(data) < 2 || data[0] != '/' {
+		return false
+	}
+
+	switch data[1] {
+	case '/':
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			r.pos += i + 1
+		} else {
+			r.pos = len(r.Data)
+		}
+	case '*':
+		i := bytes.Index(data[2:], []byte("*/"))
+		if i < 0 {
+			r.errParse("unterminated comment")
+			return false
+		}
+		r.pos += i + 4
+	default:
+		return false
+	}
+	r.start = r.pos
+	return true
+}
+
+// skipSpace skips whitespace and comments.
+func (r *Lexer) skipSpace() {
+	for r.pos < len(r.Data) {
+		switch r.Data[r.pos] {
+		case ' ', '\t', '\r', '\n':
+			r.pos++
+		case '/':
+			if !r.skipComment() {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+// fetchKey scans an unquoted object key, an identifier followed by a colon,
+// as a string token. It reports false, leaving the position unchanged, if
+// there is no identifier or it is not followed by a colon.
+func (r *Lexer) fetchKey() bool {
+	end := r.pos
+	for end < len(r.Data) && isIdentChar(r.Data[end]) {
+		end++
+	}
+	if end == r.pos {
+		return false
+	}
+
+	pos := r.pos
+	r.pos = end
+	r.skipSpace()
+	if r.pos == len(r.Data) || r.Data[r.pos] != ':' {
+		r.pos, r.start = pos, pos
+		return false
+	}
+
+	r.token.kind = TokenString
+	r.token.byteValue = r.Data[pos:end]
+	r.start, r.pos = pos, end
+	return true
+}
+
+// isHexNumber reports whether data starts with a hexadecimal number literal.
+func isHexNumber(data []byte) bool {
+	if len(data) > 0 && data[0] == '-' {
+		data = data[1:]
+	}
+	return len(data) > 2 && data[0] == '0' && (data[1] == 'x' || data[1] == 'X')
+}
+
+// fetchHexNumber scans a hexadecimal number literal. The token value is
+// converted to decimal, so it is read like any other number.
+func (r *Lexer) fetchHexNumber() {
+	end := r.pos
+	for end < len(r.Data) && !r.tokenEnds(r.Data[end]) {
+		end++
+	}
+	literal := r.Data[r.pos:end]
+	r.pos = end
+
+	neg := literal[0] == '-'
+	digits := literal[2:]
+	if neg {
+		digits = literal[3:]
+	}
+	v, err := strconv.ParseUint(string(digits), 16, 64)
+	if err != nil {
+		r.errParse("invalid hexadecimal number literal")
+		return
+	}
+
+	var value []byte
+	if neg {
+		value = append(value, '-')
+	}
+	r.token.kind = TokenNumber
+	r.token.byteValue = strconv.AppendUint(value, v, 10)
+}
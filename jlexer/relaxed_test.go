@@ -0,0 +1,119 @@
+package jlexer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelaxed(t *testing.T) {
+	for i, test := range []struct {
+		toParse   string
+		want      interface{}
+		wantError bool
+	}{
+		{toParse: `// leading comment
+			{
+				/* block */ "a": 1, // trailing comment
+				"b" /* before colon */ : [1, 2,],
+			}
+			// comment after the value`,
+			want: map[string]interface{}{"a": 1.0, "b": []interface{}{1.0, 2.0}}},
+		{toParse: `{a: 1, $b_2: 'x', null: true, true_: false, _: null}`,
+			want: map[string]interface{}{"a": 1.0, "$b_2": "x", "null": true, "true_": false, "_": nil}},
+		{toParse: `['it\'s', '"quoted"', "\'"]`, want: []interface{}{"it's", `"quoted"`, "'"}},
+		{toParse: `[0x1F, -0xff, 0X0]`, want: []interface{}{31.0, -255.0, 0.0}},
+		{toParse: `[1/*c*/,true// c
+			]`, want: []interface{}{1.0, true}},
+		{toParse: `{}/**/`, want: map[string]interface{}{}},
+
+		{toParse: `[,]`, wantError: true},
+		{toParse: `[1,,]`, wantError: true},
+		{toParse: `{"a":,}`, wantError: true},
+		{toParse: `{"a" 1}`, wantError: true},
+		{toParse: `[a]`, wantError: true},
+		{toParse: `[0xZZ]`, wantError: true},
+		{toParse: `[1 /* unterminated`, wantError: true},
+		{toParse: `[1] / 2`, wantError: true},
+	} {
+		l := Lexer{Data: []byte(test.toParse), Relaxed: true}
+		got := l.Interface()
+		l.Consumed()
+
+		err := l.Error()
+		if err != nil && !test.wantError {
+			t.Errorf("[%d, %q] Interface() error: %v", i, test.toParse, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] Interface() ok; want error", i, test.toParse)
+		} else if err == nil && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d, %q] Interface() = %v; want %v", i, test.toParse, got, test.want)
+		}
+
+		// Valid input is rejected by default and in strict mode.
+		if !test.wantError {
+			for _, l := range []Lexer{{Data: []byte(test.toParse)}, {Data: []byte(test.toParse), Relaxed: true, Strict: true}} {
+				l.Interface()
+				l.Consumed()
+				if l.Error() == nil {
+					t.Errorf("[%d, %q] Interface() ok with Relaxed: %v, Strict: %v; want error", i, test.toParse, l.Relaxed, l.Strict)
+				}
+			}
+		}
+	}
+}
+
+func TestRelaxedSkipRecursive(t *testing.T) {
+	l := Lexer{Data: []byte(`{ // comment
+		skipped: [1, {'a': 0x10,},], "b": 2 }`), Relaxed: true}
+
+	l.Delim('{')
+	if key := l.UnsafeFieldName(false); key != "skipped" {
+		t.Fatalf("UnsafeFieldName() = %q; want %q", key, "skipped")
+	}
+	l.WantColon()
+	l.SkipRecursive()
+	l.WantComma()
+	if key := l.UnsafeFieldName(false); key != "b" {
+		t.Fatalf("UnsafeFieldName() = %q; want %q", key, "b")
+	}
+	l.WantColon()
+	if v := l.Int(); v != 2 {
+		t.Errorf("Int() = %v; want 2", v)
+	}
+	l.WantComma()
+	l.Delim('}')
+	l.Consumed()
+	if err := l.Error(); err != nil {
+		t.Errorf("error: %v", err)
+	}
+}
+
+func TestRelaxedRaw(t *testing.T) {
+	for i, test := range []struct {
+		toParse string
+		want    string
+	}{
+		{toParse: `{/*c*/"a":1,}`, want: `{"a":1}`},
+		{toParse: `[1,2,]`, want: `[1,2]`},
+		{toParse: `{a: 0x10}`, want: `{"a":16}`},
+		{toParse: `'x'`, want: `"x"`},
+		{toParse: `-0x1F`, want: `-31`},
+		{toParse: `1.5e3 // comment`, want: `1.5e3`},
+		{toParse: `{b: [null, true, 'it\'s'], a: {}, b: 1}`, want: `{"b":[null,true,"it's"],"a":{},"b":1}`},
+	} {
+		l := Lexer{Data: []byte(test.toParse), Relaxed: true}
+		got := l.Raw()
+		l.Consumed()
+		if err := l.Error(); err != nil {
+			t.Errorf("[%d, %q] Raw() error: %v", i, test.toParse, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("[%d, %q] Raw() = %s; want %s", i, test.toParse, got, test.want)
+		}
+	}
+
+	l := Lexer{Data: []byte(`{a: [1,}`), Relaxed: true}
+	if got := l.Raw(); got != nil || l.Error() == nil {
+		t.Errorf("Raw() of invalid input = %s, %v; want error", got, l.Error())
+	}
+}
@@ -0,0 +1,201 @@
+package jlexer
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// checkStrictToken validates the token just fetched against the RFC 8259
+// grammar, which is more restrictive than the one accepted by default:
+// numbers must not have leading zeros or empty fractions and exponents,
+// strings must be valid UTF-8 without control characters or lone surrogate
+// escapes, and nothing but whitespace may follow the top-level value.
+func (r *Lexer) checkStrictToken() {
+	if r.fatalError != nil {
+		return
+	}
+
+	switch r.token.kind {
+	case TokenUndef:
+		return
+	case TokenNumber:
+		if !validNumber(r.token.byteValue) {
+			r.errParse("invalid number literal")
+			return
+		}
+	case TokenString:
+		if reason := validateString(r.token.byteValue); reason != "" {
+			r.errParse(reason)
+			return
+		}
+	case TokenDelim:
+		if r.token.delimValue == '{' || r.token.delimValue == '[' {
+			return
+		}
+	}
+
+	if r.depth == 0 {
+		r.checkTrailingData()
+	}
+}
+
+// checkTrailingData publishes an error if anything but whitespace follows the
+// top-level value, without consuming the input.
+func (r *Lexer) checkTrailingData() {
+	for i, c := range r.Data[r.pos:] {
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			r.AddError(&LexerError{
+				Reason: "invalid character '" + string(c) + "' after top-level value",
+				Offset: r.pos + i,
+				Data:   string(r.Data[r.pos+i:]),
+			})
+			return
+		}
+	}
+}
+
+// skipTokens skips the next value token by token, so that all of them are
+// validated and counted against the limits. It is used by SkipRecursive in
+// strict and relaxed modes and when limits are set. It does not recurse, so
+// deeply nested input cannot exhaust the stack.
+func (r *Lexer) skipTokens() {
+	var closers []byte // Closing delimiters of the open arrays and objects.
+	for {
+		// A value is expected.
+		r.scanToken()
+		if !r.Ok() {
+			return
+		}
+		switch {
+		case r.token.kind != TokenDelim:
+			r.consume()
+			if len(closers) == 0 {
+				return
+			}
+			r.WantComma()
+		case r.token.delimValue == '{':
+			closers = append(closers, '}')
+			r.consume()
+		case r.token.delimValue == '[':
+			closers = append(closers, ']')
+			r.consume()
+		default:
+			r.errSyntax()
+			return
+		}
+
+		// Close the finished arrays and objects, then expect the next member.
+		for c := closers[len(closers)-1]; r.IsDelim(c); c = closers[len(closers)-1] {
+			r.Delim(c)
+			closers = closers[:len(closers)-1]
+			if len(closers) == 0 || !r.Ok() {
+				return
+			}
+			r.WantComma()
+		}
+		if closers[len(closers)-1] == '}' {
+			r.unsafeString(true)
+			r.WantColon()
+		}
+	}
+}
+
+// ValidNumber reports whether data is a number literal as defined by RFC 8259,
+// which the lexer checks only in Strict mode.
+func ValidNumber(data []byte) bool {
+	return validNumber(data)
+}
+
+// validNumber reports whether data is a number literal as defined by RFC 8259:
+//
+//	number = [ minus ] int [ frac ] [ exp ]
+func validNumber(data []byte) bool {
+	i := 0
+	if i < len(data) && data[i] == '-' {
+		i++
+	}
+
+	switch {
+	case i < len(data) && data[i] == '0':
+		i++
+	case i < len(data) && data[i] >= '1' && data[i] <= '9':
+		for i < len(data) && isDigit(data[i]) {
+			i++
+		}
+	default:
+		return false
+	}
+
+	if i < len(data) && data[i] == '.' {
+		i++
+		if i == len(data) || !isDigit(data[i]) {
+			return false
+		}
+		for i < len(data) && isDigit(data[i]) {
+			i++
+		}
+	}
+
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		i++
+		if i < len(data) && (data[i] == '+' || data[i] == '-') {
+			i++
+		}
+		if i == len(data) || !isDigit(data[i]) {
+			return false
+		}
+		for i < len(data) && isDigit(data[i]) {
+			i++
+		}
+	}
+
+	return i == len(data)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// validateString checks the contents of a string literal, without the quotes,
+// and returns the reason it is invalid or "" if it is valid.
+func validateString(data []byte) string {
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c < 0x20 {
+			return "invalid control character in string literal"
+		}
+		if c != '\\' {
+			continue
+		}
+
+		if i+1 == len(data) {
+			return "incorrect escape symbol \\ at the end of token"
+		}
+		switch data[i+1] {
+		case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			i++
+		case 'u':
+			rr := getu4(data[i:])
+			if rr < 0 {
+				return "incorrectly escaped \\uXXXX sequence"
+			}
+			i += 5
+			if !utf16.IsSurrogate(rr) {
+				continue
+			}
+			// A high surrogate must be followed by an escaped low one.
+			rr1 := getu4(data[i+1:])
+			if rr >= 0xdc00 || utf16.DecodeRune(rr, rr1) == utf8.RuneError {
+				return "lone surrogate in \\uXXXX sequence"
+			}
+			i += 6
+		default:
+			return "incorrectly escaped bytes"
+		}
+	}
+
+	if !utf8.Valid(data) {
+		return "invalid UTF-8 in string literal"
+	}
+	return ""
+}
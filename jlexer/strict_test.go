@@ -0,0 +1,162 @@
+package jlexer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validateStrict checks data with a strict lexer both by skipping it and by
+// decoding it into an interface{}.
+func validateStrict(data []byte) (skipErr, decodeErr error) {
+	l := Lexer{Data: data, Strict: true}
+	l.SkipRecursive()
+	l.Consumed()
+
+	d := Lexer{Data: data, Strict: true}
+	d.Interface()
+	d.Consumed()
+
+	return l.Error(), d.Error()
+}
+
+// TestStrictJSONTestSuite checks the files from the test_parsing directory of
+// JSONTestSuite in testdata. Only a subset of the suite is checked in: files
+// were picked to cover each rule of the grammar (number syntax, escapes, UTF-8
+// and surrogates, structure, byte order marks, whitespace and trailing data)
+// rather than copied wholesale, so most of the suite's variants of a case
+// already covered are left out. The deep nesting files
+// n_structure_100000_opening_arrays.json and n_structure_open_array_object.json
+// are not checked in for their size; the same inputs are generated below. Of
+// the i_ files, whose result is up to the parser, the strings are kept, which
+// strict mode must reject, and a few numbers and structures, which are only
+// checked not to crash the lexer. Other files of the suite dropped into
+// testdata/JSONTestSuite with their upstream names are picked up as well.
+func TestStrictJSONTestSuite(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "JSONTestSuite", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test files found")
+	}
+
+	generated := map[string][]byte{
+		"n_structure_100000_opening_arrays.json": []byte(strings.Repeat("[", 100000)),
+		"n_structure_open_array_object.json":     []byte(strings.Repeat(`[{"":`, 50000) + "\n"),
+	}
+	for name, data := range generated {
+		t.Run(name, func(t *testing.T) {
+			if skipErr, decodeErr := validateStrict(data); skipErr == nil || decodeErr == nil {
+				t.Errorf("%.20q... accepted: %v, %v", data, skipErr, decodeErr)
+			}
+		})
+	}
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			skipErr, decodeErr := validateStrict(data)
+			switch {
+			case strings.HasPrefix(name, "y_"):
+				if skipErr != nil || decodeErr != nil {
+					t.Errorf("%q rejected: %v, %v", data, skipErr, decodeErr)
+				}
+			case strings.HasPrefix(name, "n_"):
+				if skipErr == nil || decodeErr == nil {
+					t.Errorf("%q accepted: %v, %v", data, skipErr, decodeErr)
+				}
+			case strings.HasPrefix(name, "i_string_"):
+				// Strict mode rejects invalid UTF-8 and lone surrogates.
+				if skipErr == nil || decodeErr == nil {
+					t.Errorf("%q accepted: %v, %v", data, skipErr, decodeErr)
+				}
+			}
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	for i, test := range []struct {
+		toParse    string
+		wantLax    bool // whether the default mode rejects the input
+		wantStrict bool // whether strict mode rejects the input
+	}{
+		{toParse: `{"a":[1,-0.5e+3,"😀"]}`},
+		{toParse: `[01]`, wantStrict: true},
+		{toParse: `[1.]`, wantStrict: true},
+		{toParse: `[-]`, wantLax: true, wantStrict: true},
+		{toParse: `[-01.5]`, wantStrict: true},
+		{toParse: `["\ud800"]`, wantStrict: true},
+		{toParse: `["\udc00\ud800"]`, wantStrict: true},
+		{toParse: "[\"\xff\"]", wantStrict: true},
+		{toParse: "[\"a\tb\"]", wantStrict: true},
+		{toParse: `{"a":1} {}`, wantLax: true, wantStrict: true},
+		{toParse: `1 2`, wantLax: true, wantStrict: true},
+	} {
+		for _, strict := range []bool{false, true} {
+			l := Lexer{Data: []byte(test.toParse), Strict: strict}
+			l.Interface()
+			l.Consumed()
+
+			want := test.wantLax
+			if strict {
+				want = test.wantStrict
+			}
+			if err := l.Error(); (err != nil) != want {
+				t.Errorf("[%d, %q] strict=%v Interface() error: %v; want error %v", i, test.toParse, strict, err, want)
+			}
+		}
+	}
+}
+
+func TestStrictTrailingData(t *testing.T) {
+	l := Lexer{Data: []byte(`"a" "b"`), Strict: true}
+	_ = l.String()
+	if l.Error() == nil {
+		t.Errorf("String() ok; want error for data after the top-level value")
+	}
+
+	l = Lexer{Data: []byte(`{"a":{"b":[]}}  ` + "\n")}
+	l.Strict = true
+	raw := l.Raw()
+	if string(raw) != `{"a":{"b":[]}}` {
+		t.Errorf("Raw() = %q; want the whole object", raw)
+	}
+	if err := l.Error(); err != nil {
+		t.Errorf("Raw() error: %v", err)
+	}
+}
+
+func TestStrictConversions(t *testing.T) {
+	for i, test := range []struct {
+		toParse    string
+		read       func(l *Lexer) interface{}
+		wantStrict bool // whether strict mode rejects the input, which the default mode accepts
+	}{
+		{toParse: `true`, read: func(l *Lexer) interface{} { return l.Bool() }},
+		{toParse: `1`, read: func(l *Lexer) interface{} { return l.Bool() }, wantStrict: true},
+		{toParse: `"false"`, read: func(l *Lexer) interface{} { return l.Bool() }, wantStrict: true},
+		{toParse: `"true"`, read: func(l *Lexer) interface{} { return l.BoolStr() }},
+		{toParse: `true`, read: func(l *Lexer) interface{} { return l.BoolStr() }, wantStrict: true},
+		{toParse: `"-1.5e3"`, read: func(l *Lexer) interface{} { return l.JsonNumber() }},
+		{toParse: `"foo"`, read: func(l *Lexer) interface{} { return l.JsonNumber() }, wantStrict: true},
+		{toParse: `"01"`, read: func(l *Lexer) interface{} { return l.UnsafeJsonNumber() }, wantStrict: true},
+	} {
+		for _, strict := range []bool{false, true} {
+			l := Lexer{Data: []byte(test.toParse), Strict: strict}
+			test.read(&l)
+			l.Consumed()
+
+			if err := l.Error(); (err != nil) != (strict && test.wantStrict) {
+				t.Errorf("[%d, %q] strict=%v error: %v; want error %v", i, test.toParse, strict, err, strict && test.wantStrict)
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+// nodeKind determines type of a document node.
+type nodeKind byte
+
+const (
+	kindScalar nodeKind = iota // A string, number, bool or null literal kept as raw bytes.
+	kindObject                 // An object with members in their original order.
+	kindArray                  // An array.
+)
+
+// member is a single object member. rawKey holds the original quoted key so
+// that untouched members are written back byte for byte.
+type member struct {
+	key    string
+	rawKey []byte
+	value  *node
+}
+
+// node is a minimal mutable JSON tree. Scalars are never decoded, so number
+// precision and string escaping survive a patch untouched.
+type node struct {
+	kind    nodeKind
+	raw     []byte
+	members []member
+	items   []*node
+}
+
+// decodeNode reads the next JSON value from the lexer into a tree.
+func decodeNode(in *jlexer.Lexer) *node {
+	switch in.CurrentToken() {
+	case jlexer.TokenUndef:
+		return nil
+	case jlexer.TokenDelim:
+		if in.IsDelim('{') {
+			in.Delim('{')
+			n := &node{kind: kindObject}
+			for !in.IsDelim('}') {
+				if in.CurrentToken() != jlexer.TokenString {
+					in.AddError(&jlexer.LexerError{
+						Reason: "expected string",
+						Offset: in.GetPos(),
+					})
+					return nil
+				}
+				rawKey := in.Raw()
+				key := (&jlexer.Lexer{Data: rawKey}).String()
+				in.WantColon()
+				n.members = append(n.members, member{key: key, rawKey: rawKey, value: decodeNode(in)})
+				in.WantComma()
+			}
+			in.Delim('}')
+			return n
+		}
+		if in.IsDelim('[') {
+			in.Delim('[')
+			n := &node{kind: kindArray, items: []*node{}}
+			for !in.IsDelim(']') {
+				n.items = append(n.items, decodeNode(in))
+				in.WantComma()
+			}
+			in.Delim(']')
+			return n
+		}
+		in.Delim('{')
+		return nil
+	default:
+		return &node{kind: kindScalar, raw: in.Raw()}
+	}
+}
+
+// parseNode decodes a complete JSON document.
+func parseNode(data []byte) (*node, error) {
+	in := jlexer.Lexer{Data: data}
+	n := decodeNode(&in)
+	in.Consumed()
+	if err := in.Error(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// encode writes the tree to the writer.
+func (n *node) encode(out *jwriter.Writer) {
+	switch n.kind {
+	case kindObject:
+		out.RawByte('{')
+		for i, m := range n.members {
+			if i > 0 {
+				out.RawByte(',')
+			}
+			if m.rawKey != nil {
+				out.Raw(m.rawKey, nil)
+			} else {
+				out.String(m.key)
+			}
+			out.RawByte(':')
+			m.value.encode(out)
+		}
+		out.RawByte('}')
+	case kindArray:
+		out.RawByte('[')
+		for i, v := range n.items {
+			if i > 0 {
+				out.RawByte(',')
+			}
+			v.encode(out)
+		}
+		out.RawByte(']')
+	default:
+		out.Raw(n.raw, nil)
+	}
+}
+
+// clone returns a deep copy of the tree. Raw scalar bytes are shared as they
+// are never modified in place.
+func (n *node) clone() *node {
+	c := &node{kind: n.kind, raw: n.raw}
+	if n.members != nil {
+		c.members = make([]member, len(n.members))
+		for i, m := range n.members {
+			c.members[i] = member{key: m.key, rawKey: m.rawKey, value: m.value.clone()}
+		}
+	}
+	if n.items != nil {
+		c.items = make([]*node, len(n.items))
+		for i, v := range n.items {
+			c.items[i] = v.clone()
+		}
+	}
+	return c
+}
+
+// find returns the index of the member with the given key or -1.
+func (n *node) find(key string) int {
+	for i, m := range n.members {
+		if m.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// equal reports whether two trees hold the same JSON value as defined by the
+// "test" operation of RFC 6902: member order is ignored, numbers are compared
+// by value and strings after unescaping.
+func (n *node) equal(o *node) bool {
+	if n.kind != o.kind {
+		return false
+	}
+
+	switch n.kind {
+	case kindObject:
+		if len(n.members) != len(o.members) {
+			return false
+		}
+		for _, m := range n.members {
+			i := o.find(m.key)
+			if i < 0 || !m.value.equal(o.members[i].value) {
+				return false
+			}
+		}
+		return true
+	case kindArray:
+		if len(n.items) != len(o.items) {
+			return false
+		}
+		for i := range n.items {
+			if !n.items[i].equal(o.items[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if bytes.Equal(n.raw, o.raw) {
+		return true
+	}
+
+	l1 := jlexer.Lexer{Data: n.raw}
+	l2 := jlexer.Lexer{Data: o.raw}
+	k := l1.CurrentToken()
+	if k != l2.CurrentToken() {
+		return false
+	}
+
+	switch k {
+	case jlexer.TokenString:
+		return l1.String() == l2.String()
+	case jlexer.TokenNumber:
+		var r1, r2 big.Rat
+		if _, ok := r1.SetString(string(n.raw)); !ok {
+			return false
+		}
+		if _, ok := r2.SetString(string(o.raw)); !ok {
+			return false
+		}
+		return r1.Cmp(&r2) == 0
+	}
+	return false
+}
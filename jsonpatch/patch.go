@@ -0,0 +1,395 @@
+// Package jsonpatch applies RFC 6902 JSON Patch documents to raw JSON values.
+//
+// Documents are parsed with jlexer into a lightweight tree and written back
+// with jwriter. Scalars are kept as raw literals and object members keep their
+// order, so parts of a document not touched by a patch are reproduced exactly.
+package jsonpatch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+var (
+	// ErrTestFailed is returned when a "test" operation does not match.
+	ErrTestFailed = errors.New("test operation failed")
+
+	// ErrPathNotFound is returned when an operation refers to a location that does not exist.
+	ErrPathNotFound = errors.New("path not found")
+
+	// ErrInvalidPointer is returned for malformed JSON Pointers and array indexes.
+	ErrInvalidPointer = errors.New("invalid JSON pointer")
+)
+
+// Operation is a single JSON Patch operation.
+type Operation struct {
+	Op    string
+	Path  string
+	From  string
+	Value easyjson.RawMessage
+}
+
+// MarshalEasyJSON does JSON marshaling using easyjson interface.
+func (o Operation) MarshalEasyJSON(w *jwriter.Writer) {
+	w.RawString(`{"op":`)
+	w.String(o.Op)
+	w.RawString(`,"path":`)
+	w.String(o.Path)
+	switch o.Op {
+	case "move", "copy":
+		w.RawString(`,"from":`)
+		w.String(o.From)
+	case "add", "replace", "test":
+		w.RawString(`,"value":`)
+		o.Value.MarshalEasyJSON(w)
+	}
+	w.RawByte('}')
+}
+
+// UnmarshalEasyJSON does JSON unmarshaling using easyjson interface.
+func (o *Operation) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	*o = Operation{}
+	l.Delim('{')
+	for !l.IsDelim('}') {
+		key := l.UnsafeFieldName(false)
+		l.WantColon()
+		switch key {
+		case "op":
+			o.Op = l.String()
+		case "path":
+			o.Path = l.String()
+		case "from":
+			o.From = l.String()
+		case "value":
+			o.Value = easyjson.RawMessage(l.Raw())
+		default:
+			l.SkipRecursive()
+		}
+		l.WantComma()
+	}
+	l.Delim('}')
+}
+
+// Patch is an ordered list of operations.
+type Patch []Operation
+
+// MarshalEasyJSON does JSON marshaling using easyjson interface.
+func (p Patch) MarshalEasyJSON(w *jwriter.Writer) {
+	w.RawByte('[')
+	for i, o := range p {
+		if i > 0 {
+			w.RawByte(',')
+		}
+		o.MarshalEasyJSON(w)
+	}
+	w.RawByte(']')
+}
+
+// UnmarshalEasyJSON does JSON unmarshaling using easyjson interface.
+func (p *Patch) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	*p = (*p)[:0]
+	l.Delim('[')
+	for !l.IsDelim(']') {
+		var o Operation
+		o.UnmarshalEasyJSON(l)
+		*p = append(*p, o)
+		l.WantComma()
+	}
+	l.Delim(']')
+}
+
+// DecodePatch parses a JSON Patch document.
+func DecodePatch(data []byte) (Patch, error) {
+	var p Patch
+	l := jlexer.Lexer{Data: data}
+	p.UnmarshalEasyJSON(&l)
+	l.Consumed()
+	if err := l.Error(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Apply decodes a JSON Patch document and applies it to doc.
+func Apply(doc easyjson.RawMessage, patch []byte) (easyjson.RawMessage, error) {
+	p, err := DecodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return p.Apply(doc)
+}
+
+// Apply applies all operations to doc and returns the resulting document. The
+// operations are applied atomically: if one of them fails, an error is
+// returned and no partial result is produced. doc itself is never modified.
+func (p Patch) Apply(doc easyjson.RawMessage) (easyjson.RawMessage, error) {
+	root, err := parseNode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, o := range p {
+		if root, err = o.apply(root); err != nil {
+			return nil, fmt.Errorf("jsonpatch: operation %d (%s %q): %w", i, o.Op, o.Path, err)
+		}
+	}
+
+	w := jwriter.Writer{}
+	root.encode(&w)
+	data, err := w.BuildBytes()
+	return easyjson.RawMessage(data), err
+}
+
+// apply performs the operation on the tree and returns the new root.
+func (o *Operation) apply(root *node) (*node, error) {
+	path, err := parsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case "add":
+		v, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, v)
+	case "remove":
+		_, err := remove(root, path)
+		return root, err
+	case "replace":
+		v, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := get(root, path); err != nil {
+			return nil, err
+		}
+		return set(root, path, v)
+	case "move":
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		if isProperPrefix(from, path) {
+			return nil, fmt.Errorf("cannot move %q into its own child %q", o.From, o.Path)
+		}
+		v, err := remove(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, v)
+	case "copy":
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := get(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, v.clone())
+	case "test":
+		v, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		cur, err := get(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !cur.equal(v) {
+			return nil, ErrTestFailed
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", o.Op)
+}
+
+// value parses the value member of the operation.
+func (o *Operation) value() (*node, error) {
+	if o.Value == nil {
+		return nil, errors.New("missing value")
+	}
+	return parseNode(o.Value)
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into unescaped reference tokens.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, ErrInvalidPointer
+	}
+
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		if !strings.Contains(t, "~") {
+			continue
+		}
+		for j := 0; j < len(t); j++ {
+			if t[j] == '~' && (j+1 == len(t) || (t[j+1] != '0' && t[j+1] != '1')) {
+				return nil, ErrInvalidPointer
+			}
+		}
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// isProperPrefix reports whether path a is a proper prefix of path b.
+func isProperPrefix(a, b []string) bool {
+	if len(a) >= len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex parses an array reference token. "-" is accepted only when
+// allowEnd is set and refers to the position after the last element.
+func arrayIndex(t string, length int, allowEnd bool) (int, error) {
+	if t == "-" && allowEnd {
+		return length, nil
+	}
+	if t == "" || (len(t) > 1 && t[0] == '0') {
+		return 0, ErrInvalidPointer
+	}
+	for i := 0; i < len(t); i++ {
+		if t[i] < '0' || t[i] > '9' {
+			return 0, ErrInvalidPointer
+		}
+	}
+
+	i, err := strconv.Atoi(t)
+	if err != nil {
+		return 0, ErrInvalidPointer
+	}
+	if i > length || (i == length && !allowEnd) {
+		return 0, ErrPathNotFound
+	}
+	return i, nil
+}
+
+// get returns the node referenced by path.
+func get(root *node, path []string) (*node, error) {
+	n := root
+	for _, t := range path {
+		switch n.kind {
+		case kindObject:
+			i := n.find(t)
+			if i < 0 {
+				return nil, ErrPathNotFound
+			}
+			n = n.members[i].value
+		case kindArray:
+			i, err := arrayIndex(t, len(n.items), false)
+			if err != nil {
+				return nil, err
+			}
+			n = n.items[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return n, nil
+}
+
+// add inserts v at path: object members are created or replaced, array
+// elements are inserted before the referenced index.
+func add(root *node, path []string, v *node) (*node, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch parent.kind {
+	case kindObject:
+		if i := parent.find(last); i >= 0 {
+			parent.members[i].value = v
+		} else {
+			parent.members = append(parent.members, member{key: last, value: v})
+		}
+	case kindArray:
+		i, err := arrayIndex(last, len(parent.items), true)
+		if err != nil {
+			return nil, err
+		}
+		parent.items = append(parent.items, nil)
+		copy(parent.items[i+1:], parent.items[i:])
+		parent.items[i] = v
+	default:
+		return nil, ErrPathNotFound
+	}
+	return root, nil
+}
+
+// set replaces the existing value at path with v.
+func set(root *node, path []string, v *node) (*node, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	if parent.kind == kindObject {
+		parent.members[parent.find(last)].value = v
+	} else {
+		i, _ := arrayIndex(last, len(parent.items), false)
+		parent.items[i] = v
+	}
+	return root, nil
+}
+
+// remove deletes the value at path and returns it.
+func remove(root *node, path []string) (*node, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the document root")
+	}
+
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch parent.kind {
+	case kindObject:
+		i := parent.find(last)
+		if i < 0 {
+			return nil, ErrPathNotFound
+		}
+		v := parent.members[i].value
+		parent.members = append(parent.members[:i], parent.members[i+1:]...)
+		return v, nil
+	case kindArray:
+		i, err := arrayIndex(last, len(parent.items), false)
+		if err != nil {
+			return nil, err
+		}
+		v := parent.items[i]
+		parent.items = append(parent.items[:i], parent.items[i+1:]...)
+		return v, nil
+	}
+	return nil, ErrPathNotFound
+}
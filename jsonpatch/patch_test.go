@@ -0,0 +1,194 @@
+package jsonpatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/19910211/easyjson"
+)
+
+func TestApply(t *testing.T) {
+	for i, test := range []struct {
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		// Examples from RFC 6902, Appendix A.
+		{
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"remove","path":"/baz"}]`,
+			want:  `{"foo":"bar"}`,
+		},
+		{
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			doc:   `{"foo":["all","grass","cows","eat"]}`,
+			patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			doc:   `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			want:  `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			doc:     `{"baz":"qux"}`,
+			patch:   `[{"op":"test","path":"/baz","value":"bar"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			want:  `{"foo":"bar","child":{"grandchild":{}}}`,
+		},
+		{
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			want:  `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			doc:   `{"/":9,"~1":10}`,
+			patch: `[{"op":"test","path":"/~01","value":10}]`,
+			want:  `{"/":9,"~1":10}`,
+		},
+		{
+			doc:     `{"/":9,"~1":10}`,
+			patch:   `[{"op":"test","path":"/~01","value":"10"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			want:  `{"foo":["bar",["abc","def"]]}`,
+		},
+
+		// Raw fidelity: precision, escaping and member order survive.
+		{
+			doc:   `{"z":1.000000000000000000001,"a":"é","m":[1e400]}`,
+			patch: `[{"op":"add","path":"/b","value":12345678901234567890}]`,
+			want:  `{"z":1.000000000000000000001,"a":"é","m":[1e400],"b":12345678901234567890}`,
+		},
+		{
+			doc:   `{"a":{"x":1,"y":[true,null]}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/b"},{"op":"replace","path":"/b/y/0","value":false}]`,
+			want:  `{"a":{"x":1,"y":[true,null]},"b":{"x":1,"y":[false,null]}}`,
+		},
+		{
+			doc:   `{"a":1}`,
+			patch: `[{"op":"replace","path":"","value":[1,2]}]`,
+			want:  `[1,2]`,
+		},
+		{
+			doc:   `{"n":100,"s":"A","o":{"a":1,"b":2}}`,
+			patch: `[{"op":"test","path":"/n","value":1e2},{"op":"test","path":"/s","value":"A"},{"op":"test","path":"/o","value":{"b":2,"a":1}}]`,
+			want:  `{"n":100,"s":"A","o":{"a":1,"b":2}}`,
+		},
+
+		// Errors.
+		{
+			doc:     `{"a":[1,2]}`,
+			patch:   `[{"op":"remove","path":"/a/2"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			doc:     `{"a":[1,2]}`,
+			patch:   `[{"op":"add","path":"/a/01","value":0}]`,
+			wantErr: ErrInvalidPointer,
+		},
+		{
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"replace","path":"/b","value":0}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"add","path":"a","value":0}]`,
+			wantErr: ErrInvalidPointer,
+		},
+		{
+			doc:     `{"a":{"b":1}}`,
+			patch:   `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+			wantErr: errors.New("any"),
+		},
+		{
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"add","path":"/b","value":2},{"op":"test","path":"/a","value":2}]`,
+			wantErr: ErrTestFailed,
+		},
+	} {
+		got, err := Apply(easyjson.RawMessage(test.doc), []byte(test.patch))
+		if test.wantErr != nil {
+			if err == nil {
+				t.Errorf("[%d] Apply() = %s; want error", i, got)
+			} else if test.wantErr.Error() != "any" && !errors.Is(err, test.wantErr) {
+				t.Errorf("[%d] Apply() error = %v; want %v", i, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Apply() error: %v", i, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("[%d] Apply() = %s; want %s", i, got, test.want)
+		}
+	}
+}
+
+func TestApplyDoesNotModifyInput(t *testing.T) {
+	doc := easyjson.RawMessage(`{"a":[1,2,3]}`)
+	orig := string(doc)
+
+	if _, err := Apply(doc, []byte(`[{"op":"remove","path":"/a/0"}]`)); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if string(doc) != orig {
+		t.Errorf("Apply() modified input: %s; want %s", doc, orig)
+	}
+}
+
+func TestPatchRoundTrip(t *testing.T) {
+	data := `[{"op":"add","path":"/a","value":{"b":1}},{"op":"move","path":"/c","from":"/a"},{"op":"remove","path":"/c"}]`
+
+	p, err := DecodePatch([]byte(data))
+	if err != nil {
+		t.Fatalf("DecodePatch() error: %v", err)
+	}
+
+	got, err := easyjson.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if string(got) != data {
+		t.Errorf("Marshal() = %s; want %s", got, data)
+	}
+}
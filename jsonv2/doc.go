@@ -0,0 +1,11 @@
+// Package jsonv2 bridges easyjson and encoding/json/v2.
+//
+// MarshalTo and UnmarshalFrom implement the json.MarshalerTo and
+// json.UnmarshalerFrom interfaces of encoding/json/v2 on top of easyjson
+// marshalers; generated code calls them for types generated with -jsonv2.
+// Encode and Decode do the opposite and are called by generated code for
+// field types that implement the v2 interfaces.
+//
+// encoding/json/v2 is still experimental, so the package is empty unless built
+// with Go 1.27 or later and GOEXPERIMENT=jsonv2.
+package jsonv2
@@ -0,0 +1,59 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+package jsonv2
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+// MarshalTo encodes v with its MarshalEasyJSON method and writes the result to
+// enc, which validates it and formats it according to its options.
+func MarshalTo(enc *jsontext.Encoder, v easyjson.Marshaler) error {
+	w := jwriter.Writer{}
+	v.MarshalEasyJSON(&w)
+	data, err := w.BuildBytes()
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(data)
+}
+
+// UnmarshalFrom reads the next value from dec and decodes it into v with its
+// UnmarshalEasyJSON method.
+func UnmarshalFrom(dec *jsontext.Decoder, v easyjson.Unmarshaler) error {
+	data, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	return easyjson.Unmarshal(data, v)
+}
+
+// Encode writes v encoded with its MarshalJSONTo method to w.
+func Encode(w *jwriter.Writer, v json.MarshalerTo) {
+	w.Raw(json.Marshal(v))
+}
+
+// Decode reads the next value from l and decodes it into v with its
+// UnmarshalJSONFrom method. The values v contains that implement
+// easyjson.Unmarshaler are decoded with lexers with the options of l.
+func Decode(l *jlexer.Lexer, v json.UnmarshalerFrom) {
+	data := l.Raw()
+	if !l.Ok() {
+		return
+	}
+	l.AddError(json.Unmarshal(data, v, json.WithUnmarshalers(json.UnmarshalFromFunc(
+		func(dec *jsontext.Decoder, v easyjson.Unmarshaler) error {
+			data, err := dec.ReadValue()
+			if err != nil {
+				return err
+			}
+			sub := l.Derive(data)
+			v.UnmarshalEasyJSON(sub)
+			return sub.Error()
+		}))))
+}
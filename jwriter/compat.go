@@ -0,0 +1,94 @@
+package jwriter
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// appendFloat appends n formatted with the shortest representation. With the
+// StdFloatFormat flag set the exponent is used only outside of [1e-6, 1e21),
+// as encoding/json does. NaN and infinities have no JSON representation; with
+// the NonFiniteError flag set they set the error, otherwise they are written as
+// is.
+func (w *Writer) appendFloat(b []byte, n float64, bits int) []byte {
+	if w.Flags&NonFiniteError != 0 && (math.IsNaN(n) || math.IsInf(n, 0)) {
+		if w.Error == nil {
+			w.Error = &json.UnsupportedValueError{
+				Value: reflect.ValueOf(n),
+				Str:   strconv.FormatFloat(n, 'g', -1, bits),
+			}
+		}
+		return append(b, "null"...)
+	}
+	if w.Flags&StdFloatFormat == 0 {
+		return strconv.AppendFloat(b, n, 'g', -1, bits)
+	}
+
+	format := byte('f')
+	if abs := math.Abs(n); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b = strconv.AppendFloat(b, n, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if l := len(b); l >= 4 && b[l-4] == 'e' && b[l-3] == '-' && b[l-2] == '0' {
+			b[l-2] = b[l-1]
+			b = b[:l-1]
+		}
+	}
+	return b
+}
+
+// SortedMapKeys returns the keys of m sorted by their encoded names, the order
+// encoding/json writes map entries in. Generated map encoders use it when the
+// SortMapKeys flag is set.
+func SortedMapKeys[M ~map[K]V, K comparable, V any](m M) []K {
+	type entry struct {
+		name string
+		key  K
+	}
+	entries := make([]entry, 0, len(m))
+	for k := range m {
+		entries = append(entries, entry{name: mapKeyName(reflect.ValueOf(k)), key: k})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	keys := make([]K, len(entries))
+	for i, e := range entries {
+		keys[i] = e.key
+	}
+	return keys
+}
+
+// mapKeyName returns the member name a map key is encoded as, following the
+// rules of encoding/json.
+func mapKeyName(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return ""
+		}
+		name, _ := tm.MarshalText()
+		return string(name)
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+	return fmt.Sprint(k.Interface())
+}
@@ -0,0 +1,70 @@
+package jwriter
+
+import "strings"
+
+// FieldMask selects the object members written by generated encoders that
+// support field masks. A nil mask selects everything.
+//
+// Members are referred to by their JSON names. A member mapped to a nil mask
+// is selected (or omitted, if Exclude is set) as a whole, while a member
+// mapped to a nested mask is written with the nested mask applied to its value.
+type FieldMask struct {
+	Exclude bool
+	Fields  map[string]*FieldMask
+}
+
+// IncludeFields returns a mask selecting only the given dot-separated paths,
+// e.g. IncludeFields("id", "name", "owner.email").
+func IncludeFields(paths ...string) *FieldMask {
+	return newFieldMask(false, paths)
+}
+
+// ExcludeFields returns a mask selecting everything except the given
+// dot-separated paths.
+func ExcludeFields(paths ...string) *FieldMask {
+	return newFieldMask(true, paths)
+}
+
+func newFieldMask(exclude bool, paths []string) *FieldMask {
+	m := &FieldMask{Exclude: exclude, Fields: map[string]*FieldMask{}}
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+
+		cur := m
+		names := strings.Split(p, ".")
+		for i, name := range names {
+			if i == len(names)-1 {
+				cur.Fields[name] = nil
+				break
+			}
+
+			sub, ok := cur.Fields[name]
+			if ok && sub == nil {
+				// The whole member is already selected.
+				break
+			}
+			if !ok {
+				sub = &FieldMask{Exclude: exclude, Fields: map[string]*FieldMask{}}
+				cur.Fields[name] = sub
+			}
+			cur = sub
+		}
+	}
+	return m
+}
+
+// Field reports whether the member name has to be written and returns the
+// mask to apply to its value.
+func (m *FieldMask) Field(name string) (*FieldMask, bool) {
+	if m == nil {
+		return nil, true
+	}
+
+	sub, ok := m.Fields[name]
+	if m.Exclude {
+		return sub, !ok || sub != nil
+	}
+	return sub, ok
+}
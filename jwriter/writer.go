@@ -2,11 +2,19 @@
 package jwriter
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
 	"strconv"
+	"sync/atomic"
 	"unicode/utf8"
 
 	"github.com/19910211/easyjson/buffer"
+	"github.com/19910211/easyjson/jlexer"
 )
 
 // Flags describe various encoding options. The behavior may be actually implemented in the encoder, but
@@ -16,8 +24,18 @@ type Flags int
 const (
 	NilMapAsEmpty   Flags = 1 << iota // Encode nil map as '{}' rather than 'null'.
 	NilSliceAsEmpty                   // Encode nil slice as '[]' rather than 'null'.
+	Redact                            // Encode fields tagged as sensitive as a placeholder or a hash.
+	StdFloatFormat                    // Format floats as encoding/json does, e.g. 100000000000000000000 and 1e-7 rather than 1e+20 and 1e-07.
+	SortMapKeys                       // Encode map entries sorted by key, as encoding/json does, rather than in map iteration order.
+	StdEscapes                        // Escape backspace and form feed as \b and \f, as encoding/json does, rather than as \u0008 and \u000c.
+	NonFiniteError                    // Set the error for NaN and infinite floats, as encoding/json does, rather than writing them as is.
+	StdNumbers                        // Encode json.Number as a number literal, as encoding/json does, rather than as a string.
 )
 
+// RedactedPlaceholder is written instead of the values of sensitive fields when
+// the Redact flag is set.
+const RedactedPlaceholder = "[REDACTED]"
+
 // Writer is a JSON writer.
 type Writer struct {
 	Flags Flags
@@ -25,6 +43,83 @@ type Writer struct {
 	Error        error
 	Buffer       buffer.Buffer
 	NoEscapeHTML bool
+
+	// Fields is the field mask applied by encoders generated with field mask
+	// support. Encoders replace it with the nested mask while writing a member
+	// value and restore it afterwards.
+	Fields *FieldMask
+
+	// Version is the API version to encode. Fields tagged with since=N or
+	// until=N are written only if it is in their range; 0 writes all fields.
+	Version int
+
+	// RedactKey is the HMAC key of RedactedHash. If it is nil, the key set with
+	// SetRedactKey is used.
+	RedactKey []byte
+
+	ctx context.Context // User supplied context, see Context.
+}
+
+// Context returns the context set with SetContext, or context.Background() if
+// there is none. Custom marshalers can use it to get request scoped values
+// such as the API version or locale.
+func (w *Writer) Context() context.Context {
+	if w.ctx == nil {
+		return context.Background()
+	}
+	return w.ctx
+}
+
+// SetContext sets the context returned by Context. It is shared by all
+// marshalers called with the writer.
+func (w *Writer) SetContext(ctx context.Context) {
+	w.ctx = ctx
+}
+
+// Redacted writes the placeholder for a redacted value.
+func (w *Writer) Redacted() {
+	w.String(RedactedPlaceholder)
+}
+
+var redactKey atomic.Pointer[[]byte]
+
+// SetRedactKey sets the HMAC key used by RedactedHash for writers without a
+// RedactKey. The key should be secret and long enough not to be guessed.
+func SetRedactKey(key []byte) {
+	key = append([]byte(nil), key...)
+	redactKey.Store(&key)
+}
+
+// RedactedHash writes a redacted value as the hex-encoded HMAC-SHA256 of its
+// JSON encoding, produced by encode into a separate writer with map keys
+// sorted. Equal values get equal hashes, so they can still be correlated, but
+// without the key the values can't be recovered by hashing guesses. If no key
+// is set, neither with RedactKey nor with SetRedactKey, the placeholder is
+// written instead.
+func (w *Writer) RedactedHash(encode func(*Writer)) {
+	key := w.RedactKey
+	if key == nil {
+		if k := redactKey.Load(); k != nil {
+			key = *k
+		}
+	}
+	if len(key) == 0 {
+		w.Redacted()
+		return
+	}
+
+	tmp := Writer{Flags: w.Flags | SortMapKeys, NoEscapeHTML: w.NoEscapeHTML, Fields: w.Fields, Version: w.Version, RedactKey: key, ctx: w.ctx}
+	encode(&tmp)
+	if tmp.Error != nil {
+		if w.Error == nil {
+			w.Error = tmp.Error
+		}
+		return
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(tmp.Buffer.BuildBytes())
+	w.String("hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)))
 }
 
 // Size returns the size of the data that was written out.
@@ -268,25 +363,25 @@ func (w *Writer) Int64Str(n int64) {
 
 func (w *Writer) Float32(n float32) {
 	w.Buffer.EnsureSpace(20)
-	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, float64(n), 'g', -1, 32)
+	w.Buffer.Buf = w.appendFloat(w.Buffer.Buf, float64(n), 32)
 }
 
 func (w *Writer) Float32Str(n float32) {
 	w.Buffer.EnsureSpace(20)
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
-	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, float64(n), 'g', -1, 32)
+	w.Buffer.Buf = w.appendFloat(w.Buffer.Buf, float64(n), 32)
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
 }
 
 func (w *Writer) Float64(n float64) {
 	w.Buffer.EnsureSpace(20)
-	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, n, 'g', -1, 64)
+	w.Buffer.Buf = w.appendFloat(w.Buffer.Buf, n, 64)
 }
 
 func (w *Writer) Float64Str(n float64) {
 	w.Buffer.EnsureSpace(20)
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
-	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, float64(n), 'g', -1, 64)
+	w.Buffer.Buf = w.appendFloat(w.Buffer.Buf, n, 64)
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
 }
 
@@ -299,6 +394,38 @@ func (w *Writer) Bool(v bool) {
 	}
 }
 
+func (w *Writer) BoolStr(v bool) {
+	w.Buffer.EnsureSpace(7)
+	if v {
+		w.Buffer.Buf = append(w.Buffer.Buf, `"true"`...)
+	} else {
+		w.Buffer.Buf = append(w.Buffer.Buf, `"false"`...)
+	}
+}
+
+// JsonNumber writes n as a string or, with the StdNumbers flag set, as a number
+// literal. An empty n is then written as 0, as encoding/json does, and a
+// literal that is not a valid JSON number sets the error rather than being
+// written as is.
+func (w *Writer) JsonNumber(n json.Number) {
+	if w.Flags&StdNumbers == 0 {
+		w.String(string(n))
+		return
+	}
+	if n == "" {
+		w.RawByte('0')
+		return
+	}
+	if !jlexer.ValidNumber([]byte(n)) {
+		if w.Error == nil {
+			w.Error = fmt.Errorf("json: invalid number literal %q", string(n))
+		}
+		w.RawByte('0')
+		return
+	}
+	w.RawString(string(n))
+}
+
 const chars = "0123456789abcdef"
 
 func getTable(falseValues ...int) [128]bool {
@@ -344,16 +471,20 @@ func (w *Writer) String(s string) {
 			}
 
 			w.Buffer.AppendString(s[p:i])
-			switch c {
-			case '\t':
+			switch {
+			case c == '\b' && w.Flags&StdEscapes != 0:
+				w.Buffer.AppendString(`\b`)
+			case c == '\f' && w.Flags&StdEscapes != 0:
+				w.Buffer.AppendString(`\f`)
+			case c == '\t':
 				w.Buffer.AppendString(`\t`)
-			case '\r':
+			case c == '\r':
 				w.Buffer.AppendString(`\r`)
-			case '\n':
+			case c == '\n':
 				w.Buffer.AppendString(`\n`)
-			case '\\':
+			case c == '\\':
 				w.Buffer.AppendString(`\\`)
-			case '"':
+			case c == '"':
 				w.Buffer.AppendString(`\"`)
 			default:
 				w.Buffer.AppendString(`\u00`)
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -12,13 +13,23 @@ const (
 	structComment     = "easyjson:json"
 	structSkipComment = "easyjson:skip"
 	structPoolComment = "easyjson:pool"
+	structViewComment = "easyjson:view"
 )
 
+// View describes a partial decoder requested with a
+// `//easyjson:view Name fields=A,B,C` comment on a struct type.
+type View struct {
+	Type   string
+	Name   string
+	Fields []string
+}
+
 type Parser struct {
 	PkgPath      string
 	PkgName      string
 	StructNames  []string
 	PoolStructs  map[string]struct{}
+	Views        []View
 	CloneStructs bool
 	AllStructs   bool
 }
@@ -27,13 +38,36 @@ type visitor struct {
 	*Parser
 
 	name string
+	err  error
 }
 
 func (p *Parser) needType(comments *ast.CommentGroup) (skip, explicit, pool bool) {
+	for _, comment := range commentLines(comments) {
+		if strings.HasPrefix(comment, structPoolComment) {
+			pool = true
+		}
+		if strings.HasPrefix(comment, structSkipComment) {
+			return true, false, pool
+		}
+		if strings.HasPrefix(comment, structComment) {
+			return false, true, pool
+		}
+
+		if pool {
+			return false, false, pool
+		}
+	}
+
+	return
+}
+
+// commentLines returns trimmed lines of all comments in the group.
+func commentLines(comments *ast.CommentGroup) []string {
 	if comments == nil {
-		return
+		return nil
 	}
 
+	var lines []string
 	for _, v := range comments.List {
 		comment := v.Text
 
@@ -48,25 +82,36 @@ func (p *Parser) needType(comments *ast.CommentGroup) (skip, explicit, pool bool
 			}
 		}
 
-		for _, comment := range strings.Split(comment, "\n") {
-			comment = strings.TrimSpace(comment)
-			if strings.HasPrefix(comment, structPoolComment) {
-				pool = true
-			}
-			if strings.HasPrefix(comment, structSkipComment) {
-				return true, false, pool
-			}
-			if strings.HasPrefix(comment, structComment) {
-				return false, true, pool
-			}
+		for _, line := range strings.Split(comment, "\n") {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return lines
+}
 
-			if pool {
-				return false, false, pool
+// parseViews collects `easyjson:view` comments attached to the type.
+func parseViews(typeName string, comments *ast.CommentGroup) ([]View, error) {
+	var views []View
+	for _, line := range commentLines(comments) {
+		if !strings.HasPrefix(line, structViewComment) {
+			continue
+		}
+
+		args := strings.Fields(strings.TrimPrefix(line, structViewComment))
+		if len(args) != 2 || !token.IsIdentifier(args[0]) || !strings.HasPrefix(args[1], "fields=") {
+			return nil, fmt.Errorf("%s: malformed view comment %q, expected '%s Name fields=A,B'", typeName, line, structViewComment)
+		}
+
+		view := View{Type: typeName, Name: args[0]}
+		for _, f := range strings.Split(strings.TrimPrefix(args[1], "fields="), ",") {
+			if !token.IsIdentifier(f) {
+				return nil, fmt.Errorf("%s: invalid field %q in view %s", typeName, f, view.Name)
 			}
+			view.Fields = append(view.Fields, f)
 		}
+		views = append(views, view)
 	}
-
-	return
+	return views, nil
 }
 
 func (v *visitor) Visit(n ast.Node) (w ast.Visitor) {
@@ -79,7 +124,7 @@ func (v *visitor) Visit(n ast.Node) (w ast.Visitor) {
 
 	case *ast.GenDecl:
 		skip, explicit, pool := v.needType(n.Doc)
-		if skip || explicit || pool {
+		if skip || explicit || pool || hasView(n.Doc) {
 			for _, nc := range n.Specs {
 				switch nct := nc.(type) {
 				case *ast.TypeSpec:
@@ -95,16 +140,24 @@ func (v *visitor) Visit(n ast.Node) (w ast.Visitor) {
 			v.PoolStructs[n.Name.String()] = struct{}{}
 		}
 
-		if skip {
-			return nil
-		}
-
-		if !explicit && !v.AllStructs {
+		if skip || !explicit && !v.AllStructs {
+			if hasView(n.Doc) && v.err == nil {
+				v.err = fmt.Errorf("%s: view on type not marked %s", n.Name.String(), structComment)
+			}
 			return nil
 		}
 
 		v.name = n.Name.String()
 
+		views, err := parseViews(v.name, n.Doc)
+		if _, ok := n.Type.(*ast.StructType); !ok && len(views) > 0 && err == nil {
+			err = fmt.Errorf("%s: views are supported only for struct types", v.name)
+		}
+		if err != nil && v.err == nil {
+			v.err = err
+		}
+		v.Views = append(v.Views, views...)
+
 		// Allow to specify non-structs explicitly independent of '-all' flag.
 		if explicit {
 			v.StructNames = append(v.StructNames, v.name)
@@ -133,7 +186,11 @@ func (p *Parser) Parse(fname string, isDir bool) error {
 		}
 
 		for _, pckg := range packages {
-			ast.Walk(&visitor{Parser: p}, pckg)
+			v := &visitor{Parser: p}
+			ast.Walk(v, pckg)
+			if v.err != nil {
+				return v.err
+			}
 		}
 	} else {
 		f, err := parser.ParseFile(fset, fname, nil, parser.ParseComments)
@@ -141,11 +198,25 @@ func (p *Parser) Parse(fname string, isDir bool) error {
 			return err
 		}
 
-		ast.Walk(&visitor{Parser: p}, f)
+		v := &visitor{Parser: p}
+		ast.Walk(v, f)
+		if v.err != nil {
+			return v.err
+		}
 	}
 	return nil
 }
 
+// hasView reports whether the comment group contains a view comment.
+func hasView(comments *ast.CommentGroup) bool {
+	for _, line := range commentLines(comments) {
+		if strings.HasPrefix(line, structViewComment) {
+			return true
+		}
+	}
+	return false
+}
+
 func excludeTestFiles(fi os.FileInfo) bool {
 	return !strings.HasSuffix(fi.Name(), "_test.go")
 }
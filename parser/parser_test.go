@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseViews(t *testing.T) {
+	p := Parser{PoolStructs: map[string]struct{}{}}
+	if err := p.Parse("testdata/views.go", false); err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	want := []View{{Type: "Event", Name: "EventHeader", Fields: []string{"ID", "Type"}}}
+	if !reflect.DeepEqual(p.Views, want) {
+		t.Errorf("Views = %+v; want %+v", p.Views, want)
+	}
+
+	p = Parser{PoolStructs: map[string]struct{}{}}
+	err := p.Parse("testdata/view_only.go", false)
+	if err == nil || !strings.Contains(err.Error(), "view on type not marked easyjson:json") {
+		t.Errorf("Parse() of a view-only type error = %v; want view on type not marked easyjson:json", err)
+	}
+
+	p = Parser{PoolStructs: map[string]struct{}{}, AllStructs: true}
+	if err := p.Parse("testdata/view_only.go", false); err != nil {
+		t.Fatalf("Parse() with AllStructs error: %v", err)
+	}
+	if !reflect.DeepEqual(p.Views, want) {
+		t.Errorf("Views with AllStructs = %+v; want %+v", p.Views, want)
+	}
+}
@@ -0,0 +1,8 @@
+package testdata
+
+//easyjson:view EventHeader fields=ID,Type
+type Event struct {
+	ID   int
+	Type string
+	Body string
+}
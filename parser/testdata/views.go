@@ -0,0 +1,9 @@
+package testdata
+
+//easyjson:json
+//easyjson:view EventHeader fields=ID,Type
+type Event struct {
+	ID   int
+	Type string
+	Body string
+}
@@ -0,0 +1,44 @@
+package easyjson
+
+import (
+	"errors"
+
+	"github.com/19910211/easyjson/jlexer"
+)
+
+// ErrPointerNotFound is returned when a JSON Pointer does not reference any value in the document.
+var ErrPointerNotFound = errors.New("easyjson: JSON pointer not found")
+
+// LookupRaw returns the raw bytes of the value referenced by the JSON Pointer
+// ptr (e.g. "/meta/request_id") without decoding the rest of the document. The
+// returned slice refers to data.
+func LookupRaw(data []byte, ptr string) (RawMessage, error) {
+	l := jlexer.Lexer{Data: data}
+	if !l.SeekPointer(ptr) {
+		if err := l.Error(); err != nil {
+			return nil, err
+		}
+		return nil, ErrPointerNotFound
+	}
+
+	raw := l.Raw()
+	if err := l.Error(); err != nil {
+		return nil, err
+	}
+	return RawMessage(raw), nil
+}
+
+// UnmarshalPointer decodes the value referenced by the JSON Pointer ptr into
+// v, skipping everything else in data.
+func UnmarshalPointer(data []byte, ptr string, v Unmarshaler) error {
+	l := jlexer.Lexer{Data: data}
+	if !l.SeekPointer(ptr) {
+		if err := l.Error(); err != nil {
+			return err
+		}
+		return ErrPointerNotFound
+	}
+
+	v.UnmarshalEasyJSON(&l)
+	return l.Error()
+}
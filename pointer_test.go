@@ -0,0 +1,37 @@
+package easyjson
+
+import (
+	"testing"
+
+	"github.com/19910211/easyjson/jlexer"
+)
+
+type requestID string
+
+func (v *requestID) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	*v = requestID(l.String())
+}
+
+func TestLookupRaw(t *testing.T) {
+	data := []byte(`{"meta":{"request_id":"abc","n":1.50},"body":[1,2,3]}`)
+
+	got, err := LookupRaw(data, "/meta/n")
+	if err != nil {
+		t.Fatalf("LookupRaw() error: %v", err)
+	}
+	if string(got) != "1.50" {
+		t.Errorf("LookupRaw() = %s; want 1.50", got)
+	}
+
+	if _, err := LookupRaw(data, "/meta/missing"); err != ErrPointerNotFound {
+		t.Errorf("LookupRaw() error = %v; want %v", err, ErrPointerNotFound)
+	}
+
+	var id requestID
+	if err := UnmarshalPointer(data, "/meta/request_id", &id); err != nil {
+		t.Fatalf("UnmarshalPointer() error: %v", err)
+	}
+	if id != "abc" {
+		t.Errorf("UnmarshalPointer() = %q; want %q", id, "abc")
+	}
+}
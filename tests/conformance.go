@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// The types below form the catalog of shapes the conformance tests check
+// against encoding/json.
+
+//easyjson:json
+type ConfScalars struct {
+	String  string
+	Bool    bool
+	Int     int
+	Int8    int8
+	Int16   int16
+	Int32   int32
+	Int64   int64
+	Uint    uint
+	Uint8   uint8
+	Uint16  uint16
+	Uint32  uint32
+	Uint64  uint64
+	Float32 float32
+	Float64 float64
+}
+
+//easyjson:json
+type ConfQuoted struct {
+	Int     int     `json:",string"`
+	Uint64  uint64  `json:",string"`
+	Float64 float64 `json:",string"`
+	Bool    bool    `json:",string"`
+}
+
+//easyjson:json
+type ConfFloat32 struct {
+	V float32
+}
+
+//easyjson:json
+type ConfFloat64 struct {
+	V float64
+}
+
+//easyjson:json
+type ConfString struct {
+	V string
+}
+
+//easyjson:json
+type ConfBytes struct {
+	V []byte
+}
+
+//easyjson:json
+type ConfTags struct {
+	Renamed   int    `json:"renamed"`
+	Omitted   int    `json:"-"`
+	Dash      int    `json:"-,"`
+	OmitEmpty string `json:",omitempty"`
+	Empty     string `json:""`
+	unexp     int
+}
+
+//easyjson:json
+type ConfOmitEmpty struct {
+	String string            `json:",omitempty"`
+	Int    int               `json:",omitempty"`
+	Float  float64           `json:",omitempty"`
+	Bool   bool              `json:",omitempty"`
+	Ptr    *int              `json:",omitempty"`
+	Slice  []int             `json:",omitempty"`
+	Map    map[string]int    `json:",omitempty"`
+	Struct ConfString        `json:",omitempty"`
+	Iface  interface{}       `json:",omitempty"`
+	Array  [0]int            `json:",omitempty"`
+	Named  map[string]string `json:",omitempty"`
+}
+
+//easyjson:json
+type ConfPointers struct {
+	Int    *int
+	String *string
+	Struct *ConfString
+	PtrPtr **int
+	Nil    *int
+}
+
+//easyjson:json
+type ConfCollections struct {
+	Slice       []string
+	NilSlice    []string
+	Array       [2]int
+	Nested      [][]int
+	Structs     []ConfString
+	StructPtrs  []*ConfString
+	Map         map[string]int
+	NilMap      map[string]int
+	IntMap      map[int]string
+	Uint8Map    map[uint8]bool
+	NamedKeyMap map[Str]Str
+	TextKeyMap  map[KeyWithEncodingMarshaler]string
+	MapOfSlices map[string][]int
+}
+
+//easyjson:json
+type ConfMap struct {
+	V map[string]int
+}
+
+//easyjson:json
+type ConfIntMap struct {
+	V map[int]string
+}
+
+//easyjson:json
+type ConfInterfaces struct {
+	Iface interface{}
+	Map   map[string]interface{}
+	Slice []interface{}
+}
+
+//easyjson:json
+type ConfMarshalers struct {
+	Time     time.Time
+	Duration time.Duration
+	Raw      json.RawMessage
+	Number   json.Number
+	User     vMarshaler
+	Text     tMarshaler
+}
+
+//easyjson:json
+type ConfEmbedded struct {
+	ConfEmbeddedA
+	*ConfEmbeddedB
+	Own int
+}
+
+type ConfEmbeddedA struct {
+	A     int
+	Inner ConfString
+}
+
+type ConfEmbeddedB struct {
+	B string
+}
+
+//easyjson:json
+type ConfEmbeddedTagged struct {
+	ConfEmbeddedA `json:"a"`
+	ConfEmbeddedC
+}
+
+type ConfEmbeddedC struct {
+	C int `json:"c"`
+}
+
+//easyjson:json
+type ConfCase struct {
+	FooBar string
+	Lower  string `json:"lower"`
+}
+
+//easyjson:json
+type ConfSlice []ConfString
+
+//easyjson:json
+type ConfNamedMap map[string]ConfString
+
+// ConfConflict has fields with the same JSON or Go names promoted from
+// embedded structs, resolved by the rules of encoding/json.
+//
+//easyjson:json
+type ConfConflict struct {
+	ConfConflictA
+	ConfConflictB
+	*ConfConflictP
+	Y int
+}
+
+type ConfConflictA struct {
+	X    int
+	Y    int
+	Name string `json:"a_name"`
+	Tag  int    `json:"T"`
+	ConfEmbeddedB
+}
+
+type ConfConflictB struct {
+	X    int
+	Name string `json:"b_name"`
+	T    int
+	ConfEmbeddedB
+}
+
+type ConfConflictP struct {
+	ConfConflictInner
+}
+
+type ConfConflictInner struct {
+	P string
+}
@@ -0,0 +1,11 @@
+package tests
+
+// ConfDefaults has the fields whose encoding differs from encoding/json unless
+// the generator options that remove the differences are set.
+//
+//easyjson:json
+type ConfDefaults struct {
+	Bool  bool   `json:",string"`
+	Dash  int    `json:"-,"`
+	Array [0]int `json:",omitempty"`
+}
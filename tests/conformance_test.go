@@ -0,0 +1,360 @@
+package tests
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+// divergence names an intentional difference between the generated code and
+// encoding/json. Each one is listed in the "Compatibility with encoding/json"
+// section of the README along with the option that removes it, if any. The
+// fixtures are generated with the generator options that remove divergences,
+// TestConformanceDefaults checks the output without them.
+type divergence string
+
+const (
+	floatFormat       divergence = "float_format"
+	mapKeyOrder       divergence = "map_key_order"
+	rawVerbatim       divergence = "raw_verbatim"
+	replacementEscape divergence = "replacement_escape"
+	laxSyntax         divergence = "lax_syntax"
+	laxTypes          divergence = "lax_types"
+	invalidUTF8       divergence = "invalid_utf8"
+	caseSensitiveKeys divergence = "case_sensitive_keys"
+	nullUnmarshalers  divergence = "null_unmarshalers"
+	quotedNull        divergence = "quoted_null"
+	bytesFromArray    divergence = "bytes_from_array"
+	shortEscapes      divergence = "short_escapes"
+	nonFinite         divergence = "non_finite"
+	numberString      divergence = "number_string"
+	stringBools       divergence = "string_bools"
+	dashName          divergence = "dash_name"
+	emptyArrays       divergence = "empty_arrays"
+)
+
+// encodeOptions are the writer flags that remove encoding divergences.
+var encodeOptions = map[divergence]jwriter.Flags{
+	floatFormat:  jwriter.StdFloatFormat,
+	mapKeyOrder:  jwriter.SortMapKeys,
+	shortEscapes: jwriter.StdEscapes,
+	nonFinite:    jwriter.NonFiniteError,
+	numberString: jwriter.StdNumbers,
+}
+
+// strictDivergences are the decoding divergences removed by a strict lexer.
+var strictDivergences = map[divergence]bool{
+	laxSyntax: true,
+	laxTypes:  true,
+}
+
+func marshalWithFlags(v easyjson.Marshaler, flags jwriter.Flags) ([]byte, error) {
+	w := jwriter.Writer{Flags: flags}
+	v.MarshalEasyJSON(&w)
+	return w.BuildBytes()
+}
+
+// sameJSON reports whether a and b encode the same JSON value.
+func sameJSON(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestConformanceEncode(t *testing.T) {
+	one, str := 1, "s"
+	ptrOne := &one
+
+	for _, test := range []struct {
+		name     string
+		value    easyjson.Marshaler
+		diverges divergence
+	}{
+		{name: "scalars zero", value: &ConfScalars{}},
+		{name: "scalars", value: &ConfScalars{
+			String: "a", Bool: true,
+			Int: math.MinInt64, Int8: math.MinInt8, Int16: math.MinInt16, Int32: math.MinInt32, Int64: math.MinInt64,
+			Uint: math.MaxUint64, Uint8: math.MaxUint8, Uint16: math.MaxUint16, Uint32: math.MaxUint32, Uint64: math.MaxUint64,
+			Float32: 1.1, Float64: 0.1,
+		}},
+		{name: "quoted", value: &ConfQuoted{Int: -1, Uint64: math.MaxUint64, Float64: 1.5, Bool: true}},
+		{name: "quoted zero", value: &ConfQuoted{}},
+		{name: "quoted large float", value: &ConfQuoted{Float64: 1e20}, diverges: floatFormat},
+
+		{name: "float64 1e20", value: &ConfFloat64{1e20}, diverges: floatFormat},
+		{name: "float64 1e21", value: &ConfFloat64{1e21}, diverges: floatFormat},
+		{name: "float64 1e-6", value: &ConfFloat64{1e-6}, diverges: floatFormat},
+		{name: "float64 -1e-7", value: &ConfFloat64{-1e-7}, diverges: floatFormat},
+		{name: "float64 integer", value: &ConfFloat64{123456789}, diverges: floatFormat},
+		{name: "float64 max", value: &ConfFloat64{math.MaxFloat64}, diverges: floatFormat},
+		{name: "float64 smallest", value: &ConfFloat64{math.SmallestNonzeroFloat64}, diverges: floatFormat},
+		{name: "float64 negative zero", value: &ConfFloat64{math.Copysign(0, -1)}},
+		{name: "float64 fraction", value: &ConfFloat64{-12.625}},
+		{name: "float64 NaN", value: &ConfFloat64{math.NaN()}, diverges: nonFinite},
+		{name: "float64 Inf", value: &ConfFloat64{math.Inf(-1)}, diverges: nonFinite},
+		{name: "float32 1e20", value: &ConfFloat32{1e20}, diverges: floatFormat},
+		{name: "float32 1e-7", value: &ConfFloat32{1e-7}, diverges: floatFormat},
+		{name: "float32 2^24", value: &ConfFloat32{1 << 24}, diverges: floatFormat},
+		{name: "float32 max", value: &ConfFloat32{math.MaxFloat32}, diverges: floatFormat},
+		{name: "float32 fraction", value: &ConfFloat32{0.1}},
+
+		{name: "string html", value: &ConfString{"<a href=\"x\">&amp;</a>"}},
+		{name: "string separators", value: &ConfString{"\u2028\u2029"}},
+		{name: "string escapes", value: &ConfString{"\"\\/\b\f\n\r\t"}, diverges: shortEscapes},
+		{name: "string control", value: &ConfString{"\x00\x01\x1f\x7f"}},
+		{name: "string unicode", value: &ConfString{"é😀\ufeff"}},
+		{name: "string invalid utf8", value: &ConfString{"a\xffb\xed\xa0\x80"}, diverges: replacementEscape},
+
+		{name: "bytes nil", value: &ConfBytes{}},
+		{name: "bytes empty", value: &ConfBytes{[]byte{}}},
+		{name: "bytes", value: &ConfBytes{[]byte{0, 1, 254, 255, 'a'}}},
+
+		{name: "tags", value: &ConfTags{Renamed: 1, Omitted: 2, Dash: 3, OmitEmpty: "o", Empty: "e", unexp: 4}},
+		{name: "omitempty zero", value: &ConfOmitEmpty{}},
+		{name: "omitempty empty", value: &ConfOmitEmpty{Slice: []int{}, Map: map[string]int{}, Named: map[string]string{}}},
+		{name: "omitempty set", value: &ConfOmitEmpty{
+			String: "s", Int: 1, Float: 1.5, Bool: true, Ptr: new(int), Slice: []int{1},
+			Map: map[string]int{"a": 1}, Iface: false, Named: map[string]string{"": ""},
+		}},
+
+		{name: "pointers nil", value: &ConfPointers{}},
+		{name: "pointers", value: &ConfPointers{Int: &one, String: &str, Struct: &ConfString{"x"}, PtrPtr: &ptrOne}},
+
+		{name: "collections zero", value: &ConfCollections{}},
+		{name: "collections", value: &ConfCollections{
+			Slice: []string{}, Array: [2]int{1, 2}, Nested: [][]int{nil, {}, {1}},
+			Structs: []ConfString{{}}, StructPtrs: []*ConfString{nil, {"a"}},
+			Map: map[string]int{"a": 1}, IntMap: map[int]string{-1: "a"}, Uint8Map: map[uint8]bool{255: true},
+			NamedKeyMap: map[Str]Str{"k": "v"}, TextKeyMap: map[KeyWithEncodingMarshaler]string{1: "x"},
+			MapOfSlices: map[string][]int{"a": nil},
+		}},
+		{name: "map keys", value: &ConfMap{map[string]int{"b": 1, "a": 2, "c": 3, "": 4, "é": 5}}, diverges: mapKeyOrder},
+		{name: "int map keys", value: &ConfIntMap{map[int]string{10: "a", 9: "b", -1: "c", 0: "d"}}, diverges: mapKeyOrder},
+		{name: "named map", value: &ConfNamedMap{"b": {"1"}, "a": {"2"}}, diverges: mapKeyOrder},
+		{name: "slice", value: &ConfSlice{{"a"}, {}}},
+
+		{name: "interfaces zero", value: &ConfInterfaces{}},
+		{name: "interfaces", value: &ConfInterfaces{
+			Iface: map[string]interface{}{"b": 1, "a": []interface{}{1.5, "<", nil, true}},
+			Map:   map[string]interface{}{"z": 1, "y": map[string]int{"b": 2, "a": 1}},
+			Slice: []interface{}{int64(1), uint8(2), 1e21, ConfString{"x"}},
+		}, diverges: mapKeyOrder},
+
+		{name: "marshalers zero", value: &ConfMarshalers{}, diverges: numberString},
+		{name: "marshalers", value: &ConfMarshalers{
+			Time: time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC), Duration: time.Second,
+			Raw: json.RawMessage(`{"a":[1,2]}`), Number: "-1.50e+3",
+		}, diverges: numberString},
+		{name: "raw not compact", value: &ConfMarshalers{Raw: json.RawMessage(`{ "a" : [1, 2] }`)}, diverges: rawVerbatim},
+		{name: "number injection", value: &ConfMarshalers{Number: `1,"admin":true`}, diverges: numberString},
+		{name: "number invalid", value: &ConfMarshalers{Number: " 01"}, diverges: numberString},
+
+		{name: "embedded", value: &ConfEmbedded{ConfEmbeddedA{A: 1}, &ConfEmbeddedB{B: "b"}, 2}},
+		{name: "embedded nil pointer", value: &ConfEmbedded{ConfEmbeddedA{A: 1}, nil, 2}},
+		{name: "embedded tagged", value: &ConfEmbeddedTagged{ConfEmbeddedA{A: 1}, ConfEmbeddedC{C: 2}}},
+		{name: "conflicts", value: &ConfConflict{
+			ConfConflictA: ConfConflictA{X: 1, Y: 2, Name: "a", Tag: 3, ConfEmbeddedB: ConfEmbeddedB{B: "c"}},
+			ConfConflictB: ConfConflictB{X: 5, Name: "b", T: 6, ConfEmbeddedB: ConfEmbeddedB{B: "d"}},
+			ConfConflictP: &ConfConflictP{ConfConflictInner{P: "p"}},
+			Y:             8,
+		}},
+		{name: "conflicts nil pointer", value: &ConfConflict{Y: 1}},
+		{name: "case", value: &ConfCase{FooBar: "f", Lower: "l"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			want, wantErr := json.Marshal(test.value)
+			flags, ok := encodeOptions[test.diverges]
+			if !ok && test.diverges != "" {
+				// Remove the other divergences so that only this one is left.
+				for _, f := range encodeOptions {
+					flags |= f
+				}
+			}
+			got, err := marshalWithFlags(test.value, flags)
+			if (err != nil) != (wantErr != nil) {
+				t.Fatalf("Marshal() error: %v; encoding/json: %v", err, wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			switch {
+			case test.diverges == "":
+				if string(got) != string(want) {
+					t.Errorf("Marshal() = %s\nencoding/json: %s", got, want)
+				}
+			case ok:
+				if string(got) != string(want) {
+					t.Errorf("Marshal() with the %s option = %s\nencoding/json: %s", test.diverges, got, want)
+				}
+			case !sameJSON(got, want):
+				t.Errorf("Marshal() = %s; %s must only change the encoding of\nencoding/json: %s", got, test.diverges, want)
+			}
+		})
+	}
+}
+
+func TestConformanceDecode(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		typ      easyjson.Unmarshaler // Pointer to the type to decode into.
+		data     string
+		diverges divergence
+	}{
+		{name: "scalars", typ: &ConfScalars{}, data: `{"String":"a","Bool":true,"Int":-1,"Uint64":18446744073709551615,"Float32":1.5,"Float64":-0.1e-3}`},
+		{name: "scalars null", typ: &ConfScalars{}, data: `{"String":null,"Bool":null,"Int":null,"Float64":null}`},
+		{name: "int fraction", typ: &ConfScalars{}, data: `{"Int":1.5}`},
+		{name: "int exponent", typ: &ConfScalars{}, data: `{"Int":1e3}`},
+		{name: "int8 overflow", typ: &ConfScalars{}, data: `{"Int8":128}`},
+		{name: "uint negative", typ: &ConfScalars{}, data: `{"Uint":-1}`},
+		{name: "uint64 overflow", typ: &ConfScalars{}, data: `{"Uint64":18446744073709551616}`},
+		{name: "int64 underflow", typ: &ConfScalars{}, data: `{"Int64":-9223372036854775809}`},
+		{name: "float64 overflow", typ: &ConfScalars{}, data: `{"Float64":1e400}`},
+		{name: "float32 overflow", typ: &ConfFloat32{}, data: `{"V":1e39}`},
+		{name: "int from string", typ: &ConfScalars{}, data: `{"Int":"1"}`},
+		{name: "string from number", typ: &ConfScalars{}, data: `{"String":1}`},
+		{name: "bool from number", typ: &ConfScalars{}, data: `{"Bool":1}`, diverges: laxTypes},
+		{name: "bool from string", typ: &ConfScalars{}, data: `{"Bool":"true"}`, diverges: laxTypes},
+		{name: "leading zero", typ: &ConfScalars{}, data: `{"Int":01}`, diverges: laxSyntax},
+		{name: "empty fraction", typ: &ConfScalars{}, data: `{"Float64":1.}`, diverges: laxSyntax},
+		{name: "leading dot", typ: &ConfScalars{}, data: `{"Float64":.5}`},
+		{name: "plus sign", typ: &ConfScalars{}, data: `{"Int":+1}`},
+		{name: "hex", typ: &ConfScalars{}, data: `{"Int":0x10}`},
+		{name: "NaN", typ: &ConfScalars{}, data: `{"Float64":NaN}`},
+		{name: "raw tab", typ: &ConfScalars{}, data: "{\"String\":\"a\tb\"}", diverges: laxSyntax},
+		{name: "bad escape", typ: &ConfScalars{}, data: `{"String":"\x41"}`},
+		{name: "lone surrogate", typ: &ConfScalars{}, data: `{"String":"\ud800"}`},
+		{name: "invalid utf8", typ: &ConfScalars{}, data: "{\"String\":\"a\xffb\"}", diverges: invalidUTF8},
+		{name: "unknown field", typ: &ConfScalars{}, data: `{"Unknown":[1,{"a":null}],"Int":1}`},
+		{name: "unknown field invalid", typ: &ConfScalars{}, data: `{"Unknown":01}`, diverges: laxSyntax},
+		{name: "duplicate key", typ: &ConfScalars{}, data: `{"Int":1,"Int":2}`},
+
+		{name: "trailing data", typ: &ConfScalars{}, data: `{"Int":1}x`},
+		{name: "surrounding space", typ: &ConfScalars{}, data: " \n{\"Int\":1}\t"},
+		{name: "trailing comma", typ: &ConfScalars{}, data: `{"Int":1,}`},
+		{name: "missing colon", typ: &ConfScalars{}, data: `{"Int" 1}`},
+		{name: "unterminated", typ: &ConfScalars{}, data: `{"Int":1`},
+		{name: "array for struct", typ: &ConfScalars{}, data: `[]`},
+		{name: "number for struct", typ: &ConfScalars{}, data: `1`},
+		{name: "null", typ: &ConfScalars{}, data: `null`},
+		{name: "empty", typ: &ConfScalars{}, data: ``},
+
+		{name: "quoted", typ: &ConfQuoted{}, data: `{"Int":"-1","Uint64":"18446744073709551615","Float64":"1.5","Bool":"true"}`},
+		{name: "quoted unquoted int", typ: &ConfQuoted{}, data: `{"Int":1}`},
+		{name: "quoted unquoted bool", typ: &ConfQuoted{}, data: `{"Bool":true}`, diverges: laxTypes},
+		{name: "quoted invalid", typ: &ConfQuoted{}, data: `{"Int":"x"}`},
+		{name: "quoted invalid bool", typ: &ConfQuoted{}, data: `{"Bool":"1"}`},
+		{name: "quoted null", typ: &ConfQuoted{}, data: `{"Int":null}`},
+		{name: "quoted null string", typ: &ConfQuoted{}, data: `{"Int":"null"}`, diverges: quotedNull},
+		{name: "quoted space", typ: &ConfQuoted{}, data: `{"Int":" 1"}`},
+
+		{name: "bytes", typ: &ConfBytes{}, data: `{"V":"AAH/"}`},
+		{name: "bytes url encoding", typ: &ConfBytes{}, data: `{"V":"AAH_"}`},
+		{name: "bytes no padding", typ: &ConfBytes{}, data: `{"V":"AAH"}`},
+		{name: "bytes null", typ: &ConfBytes{}, data: `{"V":null}`},
+		{name: "bytes empty", typ: &ConfBytes{}, data: `{"V":""}`},
+		{name: "bytes from array", typ: &ConfBytes{}, data: `{"V":[1,2]}`, diverges: bytesFromArray},
+
+		{name: "tags", typ: &ConfTags{}, data: `{"renamed":1,"Omitted":2,"-":3,"Dash":4,"Empty":"e","unexp":1}`},
+		{name: "case exact", typ: &ConfCase{}, data: `{"FooBar":"x","lower":"y"}`},
+		{name: "case folded", typ: &ConfCase{}, data: `{"foobar":"x","LOWER":"y"}`, diverges: caseSensitiveKeys},
+
+		{name: "pointers", typ: &ConfPointers{}, data: `{"Int":1,"String":"s","Struct":{"V":"x"},"PtrPtr":2,"Nil":null}`},
+		{name: "collections", typ: &ConfCollections{}, data: `{"Slice":["a"],"Array":[1,2],"Nested":[[1],null,[]],"StructPtrs":[null,{"V":"a"}],` +
+			`"Map":{"a":1,"a":2},"IntMap":{"-1":"a"},"Uint8Map":{"255":true},"NamedKeyMap":{"":""},"TextKeyMap":{"hello":"x"},"MapOfSlices":{"a":null}}`},
+		{name: "array overflow", typ: &ConfCollections{}, data: `{"Array":[1,2,3]}`},
+		{name: "array underflow", typ: &ConfCollections{}, data: `{"Array":[1]}`},
+		{name: "collections null", typ: &ConfCollections{}, data: `{"Slice":null,"Map":null,"Array":null}`},
+		{name: "slice from object", typ: &ConfCollections{}, data: `{"Slice":{}}`},
+		{name: "map from array", typ: &ConfCollections{}, data: `{"Map":[]}`},
+		{name: "int key invalid", typ: &ConfCollections{}, data: `{"IntMap":{"x":"a"}}`},
+		{name: "int key fraction", typ: &ConfCollections{}, data: `{"IntMap":{"1.0":"a"}}`},
+		{name: "uint8 key overflow", typ: &ConfCollections{}, data: `{"Uint8Map":{"256":true}}`},
+		{name: "slice type", typ: &ConfSlice{}, data: `[{"V":"a"},null]`},
+		{name: "named map", typ: &ConfNamedMap{}, data: `{"a":{"V":"b"},"c":null}`},
+
+		{name: "interfaces", typ: &ConfInterfaces{}, data: `{"Iface":{"a":[1,"b",null,true,{}]},"Map":{"a":{}},"Slice":[[],12345678901234567890]}`},
+		{name: "interface overflow", typ: &ConfInterfaces{}, data: `{"Iface":1e400}`},
+
+		{name: "marshalers", typ: &ConfMarshalers{}, data: `{"Time":"2020-01-02T03:04:05Z","Duration":5,"Raw":{"a" : [1]},"Number":-1.50e+3}`},
+		{name: "number from string", typ: &ConfMarshalers{}, data: `{"Number":"1.5"}`},
+		{name: "number invalid string", typ: &ConfMarshalers{}, data: `{"Number":"x"}`, diverges: laxTypes},
+		{name: "time invalid", typ: &ConfMarshalers{}, data: `{"Time":"bad"}`},
+		{name: "raw null", typ: &ConfMarshalers{}, data: `{"Raw":null}`, diverges: nullUnmarshalers},
+
+		{name: "embedded", typ: &ConfEmbedded{}, data: `{"A":1,"B":"b","Own":2,"Inner":{"V":"x"}}`},
+		{name: "embedded pointer unset", typ: &ConfEmbedded{}, data: `{"Own":2}`},
+		{name: "embedded pointer null member", typ: &ConfEmbedded{}, data: `{"B":null}`},
+		{name: "conflicts", typ: &ConfConflict{}, data: `{"X":1,"Y":2,"a_name":"a","b_name":"b","T":3,"B":"b","P":"p"}`},
+		{name: "conflicts pointer unset", typ: &ConfConflict{}, data: `{"Y":2,"T":3}`},
+		{name: "embedded tagged", typ: &ConfEmbeddedTagged{}, data: `{"a":{"A":1},"c":3}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			typ := reflect.TypeOf(test.typ).Elem()
+
+			want := reflect.New(typ).Interface()
+			wantErr := json.Unmarshal([]byte(test.data), want)
+
+			strict := strictDivergences[test.diverges]
+			got := reflect.New(typ).Interface().(easyjson.Unmarshaler)
+			l := jlexer.Lexer{Data: []byte(test.data), Strict: strict}
+			got.UnmarshalEasyJSON(&l)
+			l.Consumed()
+			err := l.Error()
+
+			if test.diverges != "" && !strict {
+				return
+			}
+			if (err != nil) != (wantErr != nil) {
+				t.Fatalf("Unmarshal(%q) error: %v; encoding/json: %v", test.data, err, wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, want) {
+				t.Errorf("Unmarshal(%q) = %+v; encoding/json: %+v", test.data, got, want)
+			}
+		})
+	}
+}
+
+// TestConformanceDefaults checks that the divergences removed by an option are
+// kept when the option is not set, so that the output of existing types does
+// not change.
+func TestConformanceDefaults(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		value    easyjson.Marshaler
+		want     string
+		diverges divergence
+	}{
+		{name: "string escapes", value: &ConfString{"\b\f"}, want: `{"V":"\u0008\u000c"}`, diverges: shortEscapes},
+		{name: "float64 NaN", value: &ConfFloat64{math.NaN()}, want: `{"V":NaN}`, diverges: nonFinite},
+		{name: "number", value: &ConfMarshalers{Number: "1.5"}, want: `"Number":"1.5"`, diverges: numberString},
+		{name: "number invalid", value: &ConfMarshalers{Number: `1,"admin":true`}, want: `"Number":"1,\"admin\":true"`, diverges: numberString},
+		{name: "string bool", value: &ConfDefaults{Bool: true}, want: `"Bool":true`, diverges: stringBools},
+		{name: "dash", value: &ConfDefaults{Dash: 1}, want: `{"Bool":false,"Array":[]}`, diverges: dashName},
+		{name: "empty array", value: &ConfDefaults{}, want: `"Array":[]`, diverges: emptyArrays},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := marshalWithFlags(test.value, 0)
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+			if !strings.Contains(string(got), test.want) {
+				t.Errorf("Marshal() = %s; want %s without the %s option", got, test.want, test.diverges)
+			}
+		})
+	}
+
+	var v ConfDefaults
+	if err := easyjson.Unmarshal([]byte(`{"Bool":true,"-":1}`), &v); err != nil || v != (ConfDefaults{Bool: true}) {
+		t.Errorf("Unmarshal() = %+v, %v; want %+v", v, err, ConfDefaults{Bool: true})
+	}
+}
@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+type localeKey struct{}
+
+// LocalizedText is written in the locale found in the writer context and
+// remembers the locale found in the lexer context when read.
+type LocalizedText struct {
+	Texts  map[string]string
+	Locale string
+}
+
+func (t LocalizedText) MarshalEasyJSON(w *jwriter.Writer) {
+	locale, _ := w.Context().Value(localeKey{}).(string)
+	w.String(t.Texts[locale])
+}
+
+func (t *LocalizedText) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	t.Locale, _ = l.Context().Value(localeKey{}).(string)
+	t.Texts = map[string]string{t.Locale: l.String()}
+}
+
+//easyjson:json
+type ContextProduct struct {
+	Title LocalizedText   `json:"title"`
+	Tags  []LocalizedText `json:"tags"`
+	Parts []ContextPart   `json:"parts"`
+}
+
+//easyjson:json
+type ContextPart struct {
+	Name *LocalizedText `json:"name"`
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/19910211/easyjson"
+)
+
+func TestMarshalContext(t *testing.T) {
+	texts := map[string]string{"en": "chair", "de": "Stuhl"}
+	v := ContextProduct{
+		Title: LocalizedText{Texts: texts},
+		Tags:  []LocalizedText{{Texts: texts}},
+		Parts: []ContextPart{{Name: &LocalizedText{Texts: texts}}},
+	}
+
+	ctx := context.WithValue(context.Background(), localeKey{}, "de")
+	got, err := easyjson.MarshalContext(ctx, &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"title":"Stuhl","tags":["Stuhl"],"parts":[{"name":"Stuhl"}]}`; string(got) != want {
+		t.Errorf("MarshalContext() = %s; want %s", got, want)
+	}
+
+	got, err = easyjson.Marshal(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"title":"","tags":[""],"parts":[{"name":""}]}`; string(got) != want {
+		t.Errorf("Marshal() = %s; want %s", got, want)
+	}
+}
+
+func TestUnmarshalContext(t *testing.T) {
+	var v ContextProduct
+	ctx := context.WithValue(context.Background(), localeKey{}, "en")
+	err := easyjson.UnmarshalContext(ctx, []byte(`{"title":"chair","tags":["wood"],"parts":[{"name":"leg"}]}`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Title.Locale != "en" || v.Tags[0].Locale != "en" || v.Parts[0].Name.Locale != "en" {
+		t.Errorf("UnmarshalContext() did not pass the context to nested unmarshalers: %+v", v)
+	}
+	if got := v.Parts[0].Name.Texts["en"]; got != "leg" {
+		t.Errorf("UnmarshalContext() name = %q; want %q", got, "leg")
+	}
+}
@@ -287,6 +287,10 @@ var structsValue = Structs{
 }
 
 var structsString = "{" +
+	// Embedded fields go in declaration order, as in encoding/json.
+	`"Value":"test",` +
+	`"V":"subp",` +
+
 	`"Value2":5,` +
 	`"substruct":{"Value":"test1","Value2":"v"},` +
 	`"Sub2":{"Value":"test2","Value2":"v2"},` +
@@ -301,11 +305,7 @@ var structsString = "{" +
 	`"Anonymous1":{"V":"bla1"},` +
 	`"AnonymousSlice":[{"V":1},{"V":2}],` +
 	`"AnonymousPtrSlice":[{"V":3},{"V":4}],` +
-	`"Slice":["test5","test6"],` +
-
-	// Embedded fields go last.
-	`"V":"subp",` +
-	`"Value":"test"` +
+	`"Slice":["test5","test6"]` +
 	"}"
 
 type OmitEmpty struct {
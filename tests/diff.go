@@ -0,0 +1,19 @@
+package tests
+
+//easyjson:json
+type DiffAccount struct {
+	ID      int               `json:"id"`
+	Name    string            `json:"name,omitempty"`
+	Address DiffAddress       `json:"address"`
+	Billing *DiffAddress      `json:"billing,omitempty"`
+	Roles   []string          `json:"roles"`
+	Labels  map[string]string `json:"a/b~c,omitempty"`
+	Counts  map[string]int64  `json:"counts,omitempty"`
+	Secret  string            `json:"-"`
+}
+
+//easyjson:json
+type DiffAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
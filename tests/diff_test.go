@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/19910211/easyjson"
+)
+
+func newDiffAccount() *DiffAccount {
+	return &DiffAccount{
+		ID:      1,
+		Name:    "alice",
+		Address: DiffAddress{City: "Berlin", Zip: "10115"},
+		Billing: &DiffAddress{City: "Paris"},
+		Roles:   []string{"admin"},
+		Labels:  map[string]string{"a": "1", "b": "2", "c": "3"},
+		Secret:  "s1",
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		modify func(v *DiffAccount)
+		want   []easyjson.Change
+	}{
+		{
+			name:   "Equal",
+			modify: func(v *DiffAccount) { v.Secret = "s2" },
+		},
+		{
+			name:   "Scalar",
+			modify: func(v *DiffAccount) { v.ID = 2 },
+			want:   []easyjson.Change{{Path: "/id", Old: []byte(`1`), New: []byte(`2`)}},
+		},
+		{
+			name:   "Omitted",
+			modify: func(v *DiffAccount) { v.Name = "" },
+			want:   []easyjson.Change{{Path: "/name", Old: []byte(`"alice"`)}},
+		},
+		{
+			name:   "Nested",
+			modify: func(v *DiffAccount) { v.Address.Zip = ""; v.Billing.City = "Rome" },
+			want: []easyjson.Change{
+				{Path: "/address/zip", Old: []byte(`"10115"`)},
+				{Path: "/billing/city", Old: []byte(`"Paris"`), New: []byte(`"Rome"`)},
+			},
+		},
+		{
+			name:   "NilPointer",
+			modify: func(v *DiffAccount) { v.Billing = nil },
+			want:   []easyjson.Change{{Path: "/billing", Old: []byte(`{"city":"Paris"}`)}},
+		},
+		{
+			name:   "Slice",
+			modify: func(v *DiffAccount) { v.Roles = nil },
+			want:   []easyjson.Change{{Path: "/roles", Old: []byte(`["admin"]`), New: []byte(`null`)}},
+		},
+		{
+			name:   "Map",
+			modify: func(v *DiffAccount) { v.Labels = map[string]string{"a": "1"} },
+			want:   []easyjson.Change{{Path: "/a~1b~0c", Old: []byte(`{"a":"1","b":"2","c":"3"}`), New: []byte(`{"a":"1"}`)}},
+		},
+		{
+			name: "LargeInteger",
+			modify: func(v *DiffAccount) {
+				v.Counts = map[string]int64{"a": 9007199254740992, "b": 9007199254740993}
+			},
+			want: []easyjson.Change{{
+				Path: "/counts",
+				Old:  []byte(`{"a":9007199254740992,"b":9007199254740992}`),
+				New:  []byte(`{"a":9007199254740992,"b":9007199254740993}`),
+			}},
+		},
+	} {
+		old, new := newDiffAccount(), newDiffAccount()
+		old.Counts = map[string]int64{"a": 9007199254740992, "b": 9007199254740992}
+		if test.name != "LargeInteger" {
+			new.Counts = old.Counts
+		}
+		test.modify(new)
+
+		got := easyjson.Diff(old, new)
+		if len(got) == 1 {
+			// Members of maps are written in random order.
+			switch test.name {
+			case "Map":
+				got[0].Old = []byte(`{"a":"1","b":"2","c":"3"}`)
+			case "LargeInteger":
+				got[0].Old = []byte(`{"a":9007199254740992,"b":9007199254740992}`)
+				got[0].New = []byte(`{"a":9007199254740992,"b":9007199254740993}`)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Diff() = %s; want %s", test.name, formatChanges(got), formatChanges(test.want))
+		}
+	}
+}
+
+func TestDiffNil(t *testing.T) {
+	v := &DiffAddress{City: "Oslo"}
+
+	got := easyjson.Diff(nil, v)
+	want := []easyjson.Change{{Path: "", New: []byte(`{"city":"Oslo"}`)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff(nil, v) = %s; want %s", formatChanges(got), formatChanges(want))
+	}
+
+	if got := easyjson.Diff[*DiffAddress](nil, nil); len(got) != 0 {
+		t.Errorf("Diff(nil, nil) = %s; want no changes", formatChanges(got))
+	}
+}
+
+func formatChanges(changes []easyjson.Change) string {
+	s := "["
+	for _, c := range changes {
+		s += "{" + c.Path + " " + string(c.Old) + " -> " + string(c.New) + "}"
+	}
+	return s + "]"
+}
@@ -0,0 +1,10 @@
+package tests
+
+//easyjson:json
+type DuplicateKeys struct {
+	Name   string            `json:"name"`
+	Amount int               `json:"amount"`
+	Labels map[string]string `json:"labels"`
+	Codes  map[int]bool      `json:"codes"`
+	Child  *DuplicateKeys    `json:"child"`
+}
@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/19910211/easyjson/jlexer"
+)
+
+func TestDuplicateKeys(t *testing.T) {
+	for i, test := range []struct {
+		data string
+		dup  string
+	}{
+		{data: `{"name":"a","amount":1,"labels":{"a":"1","b":"2"},"child":{"name":"a"}}`},
+		{data: `{"name":"a","amount":1,"name":"b"}`, dup: "name"},
+		{data: `{"name":"a","name":"b"}`, dup: "name"},
+		{data: `{"n\u0061me":"a","name":"b"}`, dup: "name"},
+		{data: `{"extra":1,"other":2,"extra":3}`, dup: "extra"},
+		{data: `{"labels":{"a":"1","a":"2"}}`, dup: "a"},
+		{data: `{"codes":{"1":true,"1":false}}`, dup: "1"},
+		{data: `{"child":{"amount":1,"amount":2}}`, dup: "amount"},
+	} {
+		var v DuplicateKeys
+		err := v.UnmarshalJSON([]byte(test.data))
+		if test.dup == "" {
+			if err != nil {
+				t.Errorf("[%d] UnmarshalJSON(%s) error: %v", i, test.data, err)
+			}
+			continue
+		}
+
+		var lexErr *jlexer.LexerError
+		if !errors.As(err, &lexErr) || lexErr.Reason != "duplicate key" || lexErr.Data != test.dup {
+			t.Errorf("[%d] UnmarshalJSON(%s) error: %v; want duplicate key %q", i, test.data, err, test.dup)
+		}
+	}
+}
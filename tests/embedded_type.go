@@ -27,4 +27,4 @@ func init() {
 	embeddedTypeValue.Field3 = 4
 }
 
-var embeddedTypeValueString = `{"Inner":{"Field1":3},"Field2":2,"named":{"Field3":4},"Field1":1}`
+var embeddedTypeValueString = `{"Field1":1,"Inner":{"Field1":3},"Field2":2,"named":{"Field3":4}}`
@@ -0,0 +1,31 @@
+package tests
+
+//easyjson:json
+type EqualOrder struct {
+	ID       int                     `json:"id"`
+	Customer EqualCustomer           `json:"customer"`
+	Billing  *EqualCustomer          `json:"billing"`
+	Lines    []*EqualLine            `json:"lines"`
+	Values   []EqualLine             `json:"values"`
+	ByKey    map[string]*EqualLine   `json:"by_key"`
+	Matrix   [][]int                 `json:"matrix"`
+	Groups   map[string][]string     `json:"groups"`
+	Fixed    [2]EqualLine            `json:"fixed"`
+	Note     *string                 `json:"note"`
+	Extra    interface{}             `json:"extra"`
+	Nested   map[string]map[int]bool `json:"nested"`
+	Cached   []byte                  `json:"-"`
+	internal int
+}
+
+//easyjson:json
+type EqualCustomer struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+//easyjson:json
+type EqualLine struct {
+	SKU   string  `json:"sku"`
+	Price float64 `json:"price"`
+}
@@ -0,0 +1,24 @@
+package tests
+
+//easyjson:json
+type EqualJSONOnly struct {
+	ID       int    `json:"id"`
+	Cached   []byte `json:"-"`
+	internal int
+}
+
+//easyjson:json
+type EqualJSONOnlyEmbedded struct {
+	equalJSONOnlyBase
+	*equalJSONOnlyExtra
+	Name string `json:"name"`
+}
+
+type equalJSONOnlyBase struct {
+	ID       int `json:"id"`
+	internal int
+}
+
+type equalJSONOnlyExtra struct {
+	Note string `json:"note"`
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newEqualOrder() *EqualOrder {
+	note := "fragile"
+	return &EqualOrder{
+		ID:       1,
+		Customer: EqualCustomer{Name: "alice", Tags: []string{"vip"}},
+		Billing:  &EqualCustomer{Name: "bob"},
+		Lines:    []*EqualLine{{SKU: "a", Price: 1}, nil},
+		Values:   []EqualLine{{SKU: "b", Price: 2}},
+		ByKey:    map[string]*EqualLine{"a": {SKU: "a"}, "nil": nil},
+		Matrix:   [][]int{{1, 2}, nil},
+		Groups:   map[string][]string{"g": {"x", "y"}},
+		Fixed:    [2]EqualLine{{SKU: "c"}},
+		Note:     &note,
+		Extra:    map[string]interface{}{"k": []interface{}{1.0}},
+		Nested:   map[string]map[int]bool{"n": {1: true}},
+		Cached:   []byte("cache"),
+		internal: 1,
+	}
+}
+
+func TestEqual(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		modify func(o *EqualOrder)
+	}{
+		{"ID", func(o *EqualOrder) { o.ID = 2 }},
+		{"Customer", func(o *EqualOrder) { o.Customer.Tags[0] = "new" }},
+		{"Billing", func(o *EqualOrder) { o.Billing = nil }},
+		{"BillingName", func(o *EqualOrder) { o.Billing.Name = "eve" }},
+		{"Lines", func(o *EqualOrder) { o.Lines[0].Price = 3 }},
+		{"LinesNil", func(o *EqualOrder) { o.Lines[1] = &EqualLine{} }},
+		{"LinesLen", func(o *EqualOrder) { o.Lines = o.Lines[:1] }},
+		{"Values", func(o *EqualOrder) { o.Values = []EqualLine{} }},
+		{"ByKey", func(o *EqualOrder) { o.ByKey["a"].SKU = "z" }},
+		{"ByKeyMissing", func(o *EqualOrder) { delete(o.ByKey, "nil"); o.ByKey["other"] = nil }},
+		{"Matrix", func(o *EqualOrder) { o.Matrix[1] = []int{} }},
+		{"Groups", func(o *EqualOrder) { o.Groups["g"][1] = "z" }},
+		{"Fixed", func(o *EqualOrder) { o.Fixed[1].Price = 1 }},
+		{"Note", func(o *EqualOrder) { s := "other"; o.Note = &s }},
+		{"Extra", func(o *EqualOrder) { o.Extra = map[string]interface{}{"k": []interface{}{2.0}} }},
+		{"Nested", func(o *EqualOrder) { o.Nested["n"][1] = false }},
+		{"Cached", func(o *EqualOrder) { o.Cached[0] = 'C' }},
+		{"internal", func(o *EqualOrder) { o.internal = 2 }},
+	} {
+		a, b := newEqualOrder(), newEqualOrder()
+		if !a.Equal(b) {
+			t.Fatalf("%s: Equal() of identical values = false", test.name)
+		}
+
+		test.modify(b)
+		if got, want := a.Equal(b), reflect.DeepEqual(a, b); got != want {
+			t.Errorf("%s: Equal() = %v; reflect.DeepEqual() = %v", test.name, got, want)
+		}
+		if b.Equal(a) != a.Equal(b) {
+			t.Errorf("%s: Equal() is not symmetric", test.name)
+		}
+	}
+}
+
+func TestEqualNil(t *testing.T) {
+	var a, b *EqualOrder
+	if !a.Equal(b) {
+		t.Error("Equal() of nil values = false")
+	}
+	if a.Equal(newEqualOrder()) || newEqualOrder().Equal(nil) {
+		t.Error("Equal() of nil and non-nil values = true")
+	}
+}
+
+func TestEqualJSONOnly(t *testing.T) {
+	a := EqualJSONOnly{ID: 1, Cached: []byte("a"), internal: 1}
+	b := EqualJSONOnly{ID: 1, Cached: []byte("b"), internal: 2}
+	if !a.Equal(&b) {
+		t.Error("Equal() compared fields not encoded to JSON")
+	}
+
+	b.ID = 2
+	if a.Equal(&b) {
+		t.Error("Equal() of values with different IDs = true")
+	}
+}
+
+func TestEqualJSONOnlyEmbedded(t *testing.T) {
+	a := EqualJSONOnlyEmbedded{equalJSONOnlyBase{ID: 1, internal: 1}, &equalJSONOnlyExtra{Note: "a"}, "n"}
+	b := EqualJSONOnlyEmbedded{equalJSONOnlyBase{ID: 1, internal: 2}, &equalJSONOnlyExtra{Note: "a"}, "n"}
+	if !a.Equal(&b) {
+		t.Error("Equal() compared fields not encoded to JSON")
+	}
+
+	b.ID = 2
+	if a.Equal(&b) {
+		t.Error("Equal() of values with different promoted IDs = true")
+	}
+
+	b.ID = 1
+	b.Note = "b"
+	if a.Equal(&b) {
+		t.Error("Equal() of values with different promoted notes = true")
+	}
+
+	b.equalJSONOnlyExtra = nil
+	if a.Equal(&b) || b.Equal(&a) {
+		t.Error("Equal() of values with nil and non-nil embedded pointers = true")
+	}
+}
@@ -0,0 +1,25 @@
+package tests
+
+import "github.com/19910211/easyjson"
+
+//easyjson:json
+type MaskedProject struct {
+	ID      int            `json:"id"`
+	Name    string         `json:"name"`
+	Owner   MaskedUser     `json:"owner"`
+	Members []*MaskedUser  `json:"members,omitempty"`
+	Labels  map[string]int `json:"labels"`
+}
+
+//easyjson:json
+type MaskedUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+//easyjson:json
+type MaskedWithUnknowns struct {
+	easyjson.UnknownFieldsProxy
+
+	ID int `json:"id"`
+}
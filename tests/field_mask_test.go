@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+var maskedProjectValue = MaskedProject{
+	ID:   1,
+	Name: "easyjson",
+	Owner: MaskedUser{
+		Name:  "alice",
+		Email: "alice@example.com",
+	},
+	Members: []*MaskedUser{
+		{Name: "bob", Email: "bob@example.com"},
+	},
+	Labels: map[string]int{"go": 1},
+}
+
+func TestFieldMask(t *testing.T) {
+	for i, test := range []struct {
+		mask *jwriter.FieldMask
+		want string
+	}{
+		{
+			mask: nil,
+			want: `{"id":1,"name":"easyjson","owner":{"name":"alice","email":"alice@example.com"},"members":[{"name":"bob","email":"bob@example.com"}],"labels":{"go":1}}`,
+		},
+		{
+			mask: jwriter.IncludeFields("id", "name", "owner.email"),
+			want: `{"id":1,"name":"easyjson","owner":{"email":"alice@example.com"}}`,
+		},
+		{
+			mask: jwriter.IncludeFields("members.name", "owner"),
+			want: `{"owner":{"name":"alice","email":"alice@example.com"},"members":[{"name":"bob"}]}`,
+		},
+		{
+			mask: jwriter.IncludeFields("owner", "owner.email"),
+			want: `{"owner":{"name":"alice","email":"alice@example.com"}}`,
+		},
+		{
+			mask: jwriter.ExcludeFields("id", "owner.email", "members.email", "labels"),
+			want: `{"name":"easyjson","owner":{"name":"alice"},"members":[{"name":"bob"}]}`,
+		},
+		{
+			mask: jwriter.IncludeFields("unknown"),
+			want: `{}`,
+		},
+	} {
+		got, err := easyjson.MarshalFields(&maskedProjectValue, test.mask)
+		if err != nil {
+			t.Errorf("[%d] MarshalFields() error: %v", i, err)
+		}
+		if string(got) != test.want {
+			t.Errorf("[%d] MarshalFields() = %s; want %s", i, got, test.want)
+		}
+	}
+}
+
+func TestFieldMaskUnknowns(t *testing.T) {
+	var v MaskedWithUnknowns
+	if err := easyjson.Unmarshal([]byte(`{"id":1,"extra":true}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := easyjson.Marshal(&v)
+	if want := `{"id":1,"extra":true}`; string(got) != want {
+		t.Errorf("Marshal() = %s; want %s", got, want)
+	}
+
+	got, _ = easyjson.MarshalFields(&v, jwriter.IncludeFields("id"))
+	if want := `{"id":1}`; string(got) != want {
+		t.Errorf("MarshalFields() = %s; want %s", got, want)
+	}
+}
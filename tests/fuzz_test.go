@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jlexer"
+)
+
+// easyValue is a pointer to a generated type.
+type easyValue[T any] interface {
+	*T
+	easyjson.Marshaler
+	easyjson.Unmarshaler
+}
+
+// checkRoundTrip decodes data into a T and, if that succeeds, checks that
+// encoding the value and decoding it again gives the same value. It also
+// checks that a strict decoder rejects everything encoding/json does.
+func checkRoundTrip[T any, P easyValue[T]](t *testing.T, data []byte) {
+	var v T
+	l := jlexer.Lexer{Data: data, Strict: true}
+	P(&v).UnmarshalEasyJSON(&l)
+	l.Consumed()
+	if l.Error() == nil && !json.Valid(data) {
+		t.Fatalf("%T: strict decoder accepted %q rejected by encoding/json", v, data)
+	}
+
+	v = *new(T)
+	if err := easyjson.Unmarshal(data, P(&v)); err != nil {
+		return
+	}
+	out, err := easyjson.Marshal(P(&v))
+	if err != nil {
+		t.Fatalf("%T: Marshal(%#v) error: %v", v, v, err)
+	}
+	if !json.Valid(out) {
+		t.Fatalf("%T: Marshal(%#v) = %q is not valid JSON", v, v, out)
+	}
+
+	var got T
+	if err := easyjson.Unmarshal(out, P(&got)); err != nil {
+		t.Fatalf("%T: Unmarshal(%q) error: %v", v, out, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Fatalf("%T: decoded %q from %q\ngot:  %#v\nwant: %#v", v, out, data, got, v)
+	}
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	for _, seed := range []string{
+		primitiveTypesString, namedPrimitiveTypesString, structsString,
+		omitEmptyString, sliceString, arrayString, arrayOverflowString,
+		mapsString, deepNestString, IntsString, mapStringStringString,
+		intKeyedMapStructValueString, mapIntStringValueString, myUInt8SliceString,
+		`{"String":"😀\u0000","Int8":-128,"Float64":1e308}`,
+		`{"Int":1.5}`, `{"IntString":"1"}`, `{"Ptr":null}`, `null`, `[]`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Invalid UTF-8 is replaced when encoding, so it does not round-trip.
+		if !utf8.Valid(data) {
+			return
+		}
+
+		checkRoundTrip[PrimitiveTypes](t, data)
+		checkRoundTrip[NamedPrimitiveTypes](t, data)
+		checkRoundTrip[Structs](t, data)
+		checkRoundTrip[OmitEmpty](t, data)
+		checkRoundTrip[Slices](t, data)
+		checkRoundTrip[Arrays](t, data)
+		checkRoundTrip[Maps](t, data)
+		checkRoundTrip[DeepNest](t, data)
+		checkRoundTrip[Ints](t, data)
+		checkRoundTrip[MapStringString](t, data)
+		checkRoundTrip[IntKeyedMapStruct](t, data)
+		checkRoundTrip[MapIntString](t, data)
+		checkRoundTrip[MyUInt8Slice](t, data)
+		checkRoundTrip[easyjson.Value](t, data)
+	})
+}
+
+func FuzzPrimitiveTypes(f *testing.F) {
+	f.Add("bla", true, int64(-1), uint64(1), 1.5)
+	f.Add(" <&>\x00\"\\", false, int64(math.MinInt64), uint64(math.MaxUint64), -1e-300)
+	f.Add("", false, int64(0), uint64(0), 0.0)
+
+	f.Fuzz(func(t *testing.T, s string, b bool, i int64, u uint64, fl float64) {
+		if !utf8.ValidString(s) || math.IsNaN(fl) || math.IsInf(fl, 0) || math.IsInf(float64(float32(fl)), 0) {
+			return
+		}
+
+		v := PrimitiveTypes{
+			String: s, Bool: b,
+			Int: int(i), Int8: int8(i), Int16: int16(i), Int32: int32(i), Int64: i,
+			Uint: uint(u), Uint8: uint8(u), Uint16: uint16(u), Uint32: uint32(u), Uint64: u,
+			IntString: int(i), Int8String: int8(i), Int16String: int16(i), Int32String: int32(i), Int64String: i,
+			UintString: uint(u), Uint8String: uint8(u), Uint16String: uint16(u), Uint32String: uint32(u), Uint64String: u,
+			Float32: float32(fl), Float64: fl,
+			Float32String: float32(fl), Float64String: fl,
+			Ptr: &s,
+		}
+
+		out, err := easyjson.Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal(%#v) error: %v", v, err)
+		}
+
+		var got PrimitiveTypes
+		if err := easyjson.Unmarshal(out, &got); err != nil {
+			t.Fatalf("Unmarshal(%q) error: %v", out, err)
+		}
+		if !reflect.DeepEqual(got, v) {
+			t.Fatalf("Unmarshal(%q) = %#v; want %#v", out, got, v)
+		}
+
+		// encoding/json reads the output back to the same value.
+		var std PrimitiveTypes
+		if err := json.Unmarshal(out, &std); err != nil {
+			t.Fatalf("json.Unmarshal(%q) error: %v", out, err)
+		}
+		if !reflect.DeepEqual(std, v) {
+			t.Fatalf("json.Unmarshal(%q) = %#v; want %#v", out, std, v)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jlexer"
+)
+
+const interfaceOptionsString = `{"Iface":{"b":1,"a":[2.5,{"c":12345678901234567890}]},"Map":{"m":{"y":-0,"x":1e400}},"Slice":[3,{"z":null}]}`
+
+func TestInterfaceUseNumber(t *testing.T) {
+	var got ConfInterfaces
+	l := jlexer.Lexer{Data: []byte(interfaceOptionsString), UseNumber: true}
+	got.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error: %v", err)
+	}
+
+	// Numbers decode the same as with json.Decoder.UseNumber.
+	var want ConfInterfaces
+	dec := json.NewDecoder(bytes.NewReader([]byte(interfaceOptionsString)))
+	dec.UseNumber()
+	if err := dec.Decode(&want); err != nil {
+		t.Fatalf("json.Decoder.Decode() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalEasyJSON() = %#v; want %#v", got, want)
+	}
+}
+
+func TestInterfaceUseInt64(t *testing.T) {
+	var got ConfInterfaces
+	l := jlexer.Lexer{Data: []byte(`{"Map":{"i":3,"f":3.5,"big":12345678901234567890},"Slice":[-1,1e2]}`), UseInt64: true}
+	got.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error: %v", err)
+	}
+
+	want := ConfInterfaces{
+		Map:   map[string]interface{}{"i": int64(3), "f": 3.5, "big": float64(12345678901234567890)},
+		Slice: []interface{}{int64(-1), float64(100)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalEasyJSON() = %#v; want %#v", got, want)
+	}
+}
+
+func TestInterfaceOrderedObjects(t *testing.T) {
+	var v ConfInterfaces
+	l := jlexer.Lexer{Data: []byte(interfaceOptionsString), OrderedObjects: true, UseNumber: true}
+	v.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error: %v", err)
+	}
+
+	// The map of the field itself is a Go map, the objects in it are ordered.
+	if _, ok := v.Map["m"].(jlexer.OrderedObject); !ok {
+		t.Errorf("Map[m] is %T; want jlexer.OrderedObject", v.Map["m"])
+	}
+
+	got, err := easyjson.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if string(got) != interfaceOptionsString {
+		t.Errorf("Marshal() = %s; want %s", got, interfaceOptionsString)
+	}
+}
@@ -0,0 +1,76 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+package tests
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"strings"
+)
+
+//easyjson:json
+type JSONv2Struct struct {
+	Name   string
+	Nested JSONv2Nested
+	Point  v2Point
+	Ptr    *v2Point
+	Points []v2Point
+}
+
+//easyjson:json
+type JSONv2Nested struct {
+	Values map[string]int
+}
+
+var errMissingComma = errors.New("v2Point: missing comma")
+
+// v2Point implements only the encoding/json/v2 interfaces and is encoded as
+// "x,y".
+type v2Point struct {
+	X, Y string
+}
+
+func (p v2Point) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return enc.WriteToken(jsontext.String(p.X + "," + p.Y))
+}
+
+func (p *v2Point) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != '"' {
+		return errors.New("v2Point: expected string")
+	}
+	x, y, ok := strings.Cut(tok.String(), ",")
+	if !ok {
+		return errMissingComma
+	}
+	p.X, p.Y = x, y
+	return nil
+}
+
+//easyjson:json
+type JSONv2Boxed struct {
+	Box v2Box
+}
+
+//easyjson:json
+type JSONv2Flag struct {
+	On bool
+}
+
+// v2Box implements only the encoding/json/v2 interfaces and holds a generated
+// type.
+type v2Box struct {
+	Flag JSONv2Flag
+}
+
+func (b v2Box) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return json.MarshalEncode(enc, b.Flag)
+}
+
+func (b *v2Box) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return json.UnmarshalDecode(dec, &b.Flag)
+}
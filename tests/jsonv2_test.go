@@ -0,0 +1,101 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+package tests
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jlexer"
+)
+
+var jsonv2Value = JSONv2Struct{
+	Name:   "a",
+	Nested: JSONv2Nested{Values: map[string]int{"x": 1}},
+	Point:  v2Point{"1", "2"},
+	Ptr:    &v2Point{"3", "4"},
+	Points: []v2Point{{"5", "6"}},
+}
+
+const jsonv2String = `{"Name":"a","Nested":{"Values":{"x":1}},"Point":"1,2","Ptr":"3,4","Points":["5,6"]}`
+
+func TestJSONv2Fields(t *testing.T) {
+	data, err := easyjson.Marshal(jsonv2Value)
+	if err != nil {
+		t.Fatalf("easyjson.Marshal() error: %v", err)
+	}
+	if string(data) != jsonv2String {
+		t.Errorf("easyjson.Marshal() = %s; want %s", data, jsonv2String)
+	}
+
+	var got JSONv2Struct
+	if err := easyjson.Unmarshal([]byte(jsonv2String), &got); err != nil {
+		t.Fatalf("easyjson.Unmarshal() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, jsonv2Value) {
+		t.Errorf("easyjson.Unmarshal() = %+v; want %+v", got, jsonv2Value)
+	}
+
+	err = easyjson.Unmarshal([]byte(`{"Point":"12"}`), &got)
+	if !errors.Is(err, errMissingComma) {
+		t.Errorf("easyjson.Unmarshal() error: %v; want %v", err, errMissingComma)
+	}
+}
+
+func TestJSONv2Adapters(t *testing.T) {
+	wrapped := struct{ V JSONv2Struct }{jsonv2Value}
+	data, err := json.Marshal(wrapped)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if want := `{"V":` + jsonv2String + `}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s; want %s", data, want)
+	}
+
+	data, err = json.Marshal(jsonv2Value, jsontext.Multiline(true))
+	if err != nil {
+		t.Fatalf("json.Marshal(Multiline) error: %v", err)
+	}
+	var indented jsontext.Value = []byte(jsonv2String)
+	if err := indented.Indent(); err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(indented) {
+		t.Errorf("json.Marshal(Multiline) = %s; want %s", data, indented)
+	}
+
+	var got struct{ V JSONv2Struct }
+	if err := json.Unmarshal([]byte(`{"V":`+jsonv2String+`}`), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, wrapped) {
+		t.Errorf("json.Unmarshal() = %+v; want %+v", got, wrapped)
+	}
+
+	for _, data := range []string{`{"Name":1}`, `{"Name":"a"`, `{"Point":"12"}`} {
+		var v JSONv2Struct
+		if err := json.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("json.Unmarshal(%s) ok; want error", data)
+		}
+	}
+}
+
+func TestJSONv2LexerOptions(t *testing.T) {
+	data := []byte(`{"Box":{"On":1}}`)
+
+	var v JSONv2Boxed
+	if err := easyjson.Unmarshal(data, &v); err != nil || !v.Box.Flag.On {
+		t.Errorf("easyjson.Unmarshal() = %+v, %v; want true, nil", v, err)
+	}
+
+	// The generated type inside the v2 one is decoded with a strict lexer too.
+	l := jlexer.Lexer{Data: data, Strict: true}
+	v.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err == nil {
+		t.Errorf("UnmarshalEasyJSON() with a strict lexer ok; want error")
+	}
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jlexer"
+)
+
+func TestUnmarshalLimited(t *testing.T) {
+	limits := jlexer.Limits{MaxDepth: 8, MaxStringLen: 64, MaxElements: 100, MaxTokens: 1000}
+
+	for i, test := range []struct {
+		data      string
+		wantError string
+	}{
+		{data: `{"name":"api","hosts":["a","b"],"labels":{"a":"b"},"server":{"addr":"x"}}`},
+		{data: `{"hosts":[` + strings.Repeat(`"h",`, 100) + `"h"]}`, wantError: "maximum number of elements exceeded"},
+		{data: `{"labels":{` + strings.Repeat(`"k":"v",`, 100) + `"k":"v"}}`, wantError: "maximum number of elements exceeded"},
+		{data: `{"name":"` + strings.Repeat("x", 65) + `"}`, wantError: "maximum string length exceeded"},
+		{data: `{"extra":` + strings.Repeat("[", 8) + strings.Repeat("]", 8) + `}`, wantError: "maximum nesting depth exceeded"},
+		{data: `{"unknown":` + strings.Repeat("[", 100000) + strings.Repeat("]", 100000) + `}`, wantError: "maximum nesting depth exceeded"},
+		{data: `{"extra":[` + strings.Repeat(`[1,1,1,1,1,1,1,1,1,1],`, 99) + `[]]}`, wantError: "maximum number of tokens exceeded"},
+	} {
+		var v MergeConfig
+		err := easyjson.UnmarshalLimited([]byte(test.data), &v, limits)
+		switch {
+		case test.wantError == "" && err != nil:
+			t.Errorf("[%d] UnmarshalLimited() error: %v", i, err)
+		case test.wantError != "" && (err == nil || !strings.Contains(err.Error(), test.wantError)):
+			t.Errorf("[%d] UnmarshalLimited() error: %v; want %q", i, err, test.wantError)
+		}
+	}
+}
+
+func TestUnmarshalLimitedCollections(t *testing.T) {
+	limits := jlexer.Limits{MaxElements: 2}
+
+	for i, test := range []struct {
+		data      string
+		v         easyjson.Unmarshaler
+		wantError string
+	}{
+		{data: `[{"V":"a"},{"V":"b"}]`, v: &ConfSlice{}},
+		{data: `[{"V":"a"},{"V":"b"},{"V":"c"}]`, v: &ConfSlice{}, wantError: "maximum number of elements exceeded"},
+		{data: `{"a":{"V":"a"},"b":{"V":"b"}}`, v: &ConfNamedMap{}},
+		{data: `{"a":{"V":"a"},"b":{"V":"b"},"c":{"V":"c"}}`, v: &ConfNamedMap{}, wantError: "maximum number of elements exceeded"},
+		{data: `{"Slice":["a","b","c"]}`, v: &ConfCollections{}, wantError: "maximum number of elements exceeded"},
+		{data: `{"IntMap":{"1":"a","2":"b","3":"c"}}`, v: &ConfCollections{}, wantError: "maximum number of elements exceeded"},
+	} {
+		err := easyjson.UnmarshalLimited([]byte(test.data), test.v, limits)
+		switch {
+		case test.wantError == "" && err != nil:
+			t.Errorf("[%d] UnmarshalLimited() error: %v", i, err)
+		case test.wantError != "" && (err == nil || !strings.Contains(err.Error(), test.wantError)):
+			t.Errorf("[%d] UnmarshalLimited() error: %v; want %q", i, err, test.wantError)
+		}
+	}
+}
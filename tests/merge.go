@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/19910211/easyjson/opt"
+)
+
+//easyjson:json
+type MergeConfig struct {
+	Name     string            `json:"name"`
+	Port     int               `json:"port"`
+	Debug    bool              `json:"debug"`
+	Timeout  opt.Int           `json:"timeout"`
+	Started  time.Time         `json:"started"`
+	Server   MergeServer       `json:"server"`
+	Backup   *MergeServer      `json:"backup"`
+	Hosts    []string          `json:"hosts"`
+	Plugins  []string          `json:"plugins,merge=append"`
+	Labels   map[string]string `json:"labels"`
+	Env      map[string]string `json:"env,merge=replace"`
+	Checksum [4]byte           `json:"checksum"`
+	Extra    interface{}       `json:"extra"`
+	Meta     MergeMeta         `json:"meta"`
+	Args     [2]interface{}    `json:"args"`
+}
+
+// MergeMeta has no Merge method and holds an interface, so it is not safely
+// comparable with ==.
+type MergeMeta struct {
+	Value interface{} `json:"value"`
+}
+
+//easyjson:json
+type MergeServer struct {
+	Addr string `json:"addr"`
+	TLS  *bool  `json:"tls"`
+}
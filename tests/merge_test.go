@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/19910211/easyjson/opt"
+)
+
+func TestMerge(t *testing.T) {
+	yes := true
+	started := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	base := MergeConfig{
+		Name:    "base",
+		Port:    80,
+		Timeout: opt.OInt(30),
+		Server:  MergeServer{Addr: "localhost"},
+		Backup:  &MergeServer{Addr: "backup"},
+		Hosts:   []string{"a", "b"},
+		Plugins: []string{"log"},
+		Labels:  map[string]string{"team": "core", "tier": "1"},
+		Env:     map[string]string{"HOME": "/root"},
+	}
+	overlay := MergeConfig{
+		Port:     8080,
+		Debug:    true,
+		Timeout:  opt.OInt(0),
+		Started:  started,
+		Server:   MergeServer{TLS: &yes},
+		Backup:   &MergeServer{Addr: "standby"},
+		Hosts:    []string{"c"},
+		Plugins:  []string{"trace"},
+		Labels:   map[string]string{"tier": "2"},
+		Env:      map[string]string{"PATH": "/bin"},
+		Checksum: [4]byte{1},
+		Extra:    "x",
+	}
+	want := MergeConfig{
+		Name:     "base",
+		Port:     8080,
+		Debug:    true,
+		Timeout:  opt.OInt(0),
+		Started:  started,
+		Server:   MergeServer{Addr: "localhost", TLS: &yes},
+		Backup:   &MergeServer{Addr: "standby"},
+		Hosts:    []string{"c"},
+		Plugins:  []string{"log", "trace"},
+		Labels:   map[string]string{"team": "core", "tier": "2"},
+		Env:      map[string]string{"PATH": "/bin"},
+		Checksum: [4]byte{1},
+		Extra:    "x",
+	}
+
+	got := base
+	got.Merge(&overlay)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+
+	// Replaced slices must not share memory with the overlay.
+	got.Hosts[0] = "changed"
+	if overlay.Hosts[0] != "c" {
+		t.Errorf("Merge() shares the Hosts slice with src")
+	}
+}
+
+func TestMergeEmptySource(t *testing.T) {
+	base := MergeConfig{Name: "base", Hosts: []string{"a"}, Timeout: opt.OInt(5)}
+	got := base
+	got.Merge(&MergeConfig{})
+	if !reflect.DeepEqual(got, base) {
+		t.Errorf("Merge() with empty src = %+v, want %+v", got, base)
+	}
+
+	got.Merge(nil)
+	if !reflect.DeepEqual(got, base) {
+		t.Errorf("Merge(nil) = %+v, want %+v", got, base)
+	}
+}
+
+func TestMergeUncomparableInterfaces(t *testing.T) {
+	src := MergeConfig{
+		Meta: MergeMeta{Value: []int{1, 2}},
+		Args: [2]interface{}{map[string]int{"a": 1}},
+	}
+	var got MergeConfig
+	got.Merge(&src)
+
+	if !reflect.DeepEqual(got.Meta, src.Meta) || !reflect.DeepEqual(got.Args, src.Args) {
+		t.Errorf("Merge() = %+v, %+v; want %+v, %+v", got.Meta, got.Args, src.Meta, src.Args)
+	}
+
+	got.Merge(&MergeConfig{})
+	if !reflect.DeepEqual(got.Meta, src.Meta) {
+		t.Errorf("Merge() with empty src Meta = %+v, want %+v", got.Meta, src.Meta)
+	}
+}
+
+func TestMergeNilPointer(t *testing.T) {
+	src := MergeConfig{Backup: &MergeServer{Addr: "backup"}}
+	var got MergeConfig
+	got.Merge(&src)
+
+	if got.Backup == nil || got.Backup.Addr != "backup" {
+		t.Fatalf("Merge() Backup = %+v, want addr backup", got.Backup)
+	}
+	if got.Backup == src.Backup {
+		t.Errorf("Merge() shares the Backup pointer with src")
+	}
+}
@@ -0,0 +1,12 @@
+package tests
+
+import "github.com/19910211/easyjson"
+
+//easyjson:json
+type OrderedUnknowns struct {
+	easyjson.OrderedUnknownFields
+
+	A string
+	B int `json:",omitempty"`
+	C bool
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/19910211/easyjson"
+)
+
+func TestOrderedUnknownFields(t *testing.T) {
+	data := []byte(`{"x":1,"Field1":"a","y":{"z" : [1, 2.50]},"x":"2"}`)
+
+	var s StructWithOrderedUnknowns
+	if err := easyjson.Unmarshal(data, &s); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	defer s.Reset()
+
+	// The input must not be referenced after decoding.
+	copy(data, bytes.Repeat([]byte(" "), len(data)))
+
+	got, err := easyjson.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"Field1":"a","x":1,"y":{"z" : [1, 2.50]},"x":"2"}`; string(got) != want {
+		t.Errorf("Marshal() = %s; want %s", got, want)
+	}
+
+	if v, ok := s.Get("x"); !ok || string(v) != `"2"` {
+		t.Errorf(`Get("x") = %s, %v; want "2", true`, v, ok)
+	}
+	if v, ok := s.Get("Field1"); ok {
+		t.Errorf(`Get("Field1") = %s, %v; want false`, v, ok)
+	}
+
+	s.Set("x", []byte(`3`))
+	s.Set("w", []byte(`[]`))
+	s.Delete("y")
+	got, err = easyjson.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"Field1":"a","x":3,"w":[]}`; string(got) != want {
+		t.Errorf("Marshal() after changes = %s; want %s", got, want)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d; want 2", s.Len())
+	}
+
+	s.Reset()
+	if s.Len() != 0 {
+		t.Errorf("Len() after Reset() = %d; want 0", s.Len())
+	}
+}
+
+func TestOrderedUnknownsPosition(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "no unknowns",
+			in:   `{"A":"a","B":1,"C":true}`,
+			want: `{"A":"a","B":1,"C":true}`,
+		},
+		{
+			name: "all positions",
+			in:   `{"u0":0,"A":"a","u1":1,"u2":2,"B":1,"C":true,"u3":3}`,
+			want: `{"u0":0,"A":"a","u1":1,"u2":2,"B":1,"C":true,"u3":3}`,
+		},
+		{
+			name: "after skipped field",
+			in:   `{"A":"a","B":0,"u":{},"C":false}`,
+			want: `{"A":"a","u":{},"C":false}`,
+		},
+		{
+			name: "known fields reordered",
+			in:   `{"C":true,"u":1,"A":"a"}`,
+			want: `{"A":"a","C":true,"u":1}`,
+		},
+		{
+			name: "repeated keys",
+			in:   `{"u":1,"A":"a","u":2,"u":3,"C":false}`,
+			want: `{"u":1,"A":"a","u":2,"u":3,"C":false}`,
+		},
+		{
+			name: "first field skipped",
+			in:   `{"u":null,"B":1}`,
+			want: `{"u":null,"A":"","B":1,"C":false}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var v OrderedUnknowns
+			if err := easyjson.Unmarshal([]byte(tc.in), &v); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			defer v.Reset()
+
+			got, err := easyjson.Marshal(v)
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Marshal() = %s; want %s", got, tc.want)
+			}
+		})
+	}
+
+	var v OrderedUnknowns
+	if err := easyjson.Unmarshal([]byte(`{"A":"a","u":1}`), &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	v.Set("u", []byte(`2`))
+	v.Set("n", []byte(`"new"`))
+	got, err := easyjson.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"A":"a","u":2,"C":false,"n":"new"}`; string(got) != want {
+		t.Errorf("Marshal() after Set() = %s; want %s", got, want)
+	}
+}
@@ -0,0 +1,18 @@
+package tests
+
+//easyjson:json
+type RedactedUser struct {
+	ID       int               `json:"id"`
+	Email    string            `json:"email,sensitive"`
+	Password string            `json:"password,redact"`
+	Phone    *string           `json:"phone,sensitive=hash"`
+	Card     RedactedCard      `json:"card"`
+	Tokens   []string          `json:"tokens,omitempty,sensitive"`
+	Extra    map[string]string `json:"extra,redact=hash"`
+}
+
+//easyjson:json
+type RedactedCard struct {
+	Number string `json:"number,sensitive"`
+	Brand  string `json:"brand"`
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+var redactKey = []byte("test key")
+
+// setRedactKey sets the key used by MarshalRedacted for the duration of the test.
+func setRedactKey(t *testing.T, key []byte) {
+	jwriter.SetRedactKey(key)
+	t.Cleanup(func() { jwriter.SetRedactKey(nil) })
+}
+
+func redactedHash(s string) string {
+	mac := hmac.New(sha256.New, redactKey)
+	mac.Write([]byte(s))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRedact(t *testing.T) {
+	setRedactKey(t, redactKey)
+
+	phone := "555-0100"
+	v := RedactedUser{
+		ID:       1,
+		Email:    "alice@example.com",
+		Password: "secret",
+		Phone:    &phone,
+		Card:     RedactedCard{Number: "4111111111111111", Brand: "visa"},
+		Tokens:   []string{"t1"},
+		Extra:    map[string]string{"k": "v"},
+	}
+
+	got, err := easyjson.Marshal(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":1,"email":"alice@example.com","password":"secret","phone":"555-0100",` +
+		`"card":{"number":"4111111111111111","brand":"visa"},"tokens":["t1"],"extra":{"k":"v"}}`
+	if string(got) != want {
+		t.Errorf("Marshal() = %s; want %s", got, want)
+	}
+
+	got, err = easyjson.MarshalRedacted(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `{"id":1,"email":"[REDACTED]","password":"[REDACTED]","phone":"` + redactedHash(`"555-0100"`) + `",` +
+		`"card":{"number":"[REDACTED]","brand":"visa"},"tokens":"[REDACTED]","extra":"` + redactedHash(`{"k":"v"}`) + `"}`
+	if string(got) != want {
+		t.Errorf("MarshalRedacted() = %s; want %s", got, want)
+	}
+}
+
+func TestRedactOmitEmpty(t *testing.T) {
+	setRedactKey(t, redactKey)
+
+	got, err := easyjson.MarshalRedacted(&RedactedUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":0,"email":"[REDACTED]","password":"[REDACTED]","phone":"` + redactedHash(`null`) + `",` +
+		`"card":{"number":"[REDACTED]","brand":""},"extra":"` + redactedHash(`null`) + `"}`
+	if string(got) != want {
+		t.Errorf("MarshalRedacted() = %s; want %s", got, want)
+	}
+}
+
+func TestRedactHashMapOrder(t *testing.T) {
+	setRedactKey(t, redactKey)
+
+	v := RedactedUser{Extra: map[string]string{}}
+	for i := 0; i < 20; i++ {
+		v.Extra[string(rune('a'+i))] = "v"
+	}
+
+	want, err := easyjson.MarshalRedacted(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		got, err := easyjson.MarshalRedacted(&v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("MarshalRedacted() = %s; want %s", got, want)
+		}
+	}
+}
+
+func TestRedactHashKey(t *testing.T) {
+	phone := "555-0100"
+	v := RedactedUser{Phone: &phone}
+
+	w := jwriter.Writer{Flags: jwriter.Redact, RedactKey: []byte("other key")}
+	v.MarshalEasyJSON(&w)
+	got, err := w.BuildBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, []byte("other key"))
+	mac.Write([]byte(`"555-0100"`))
+	want := `"phone":"hmac-sha256:` + hex.EncodeToString(mac.Sum(nil)) + `"`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("MarshalEasyJSON() = %s; want %s", got, want)
+	}
+
+	got, err = easyjson.MarshalRedacted(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `"phone":"[REDACTED]"`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("MarshalRedacted() without a key = %s; want %s", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/opt"
+)
+
+func TestRelaxedConfig(t *testing.T) {
+	data := []byte(`// service configuration
+{
+	name: 'api',
+	port: 0x1F90, // 8080
+	timeout: 30,
+	/* the primary server */
+	server: {addr: 'localhost:8080',},
+	hosts: [
+		'a.example.com',
+		'b.example.com',
+	],
+	labels: {'team': "core",},
+	unknown: {nested: [1, 2,], note: 'skipped',},
+}
+`)
+
+	var got MergeConfig
+	l := jlexer.Lexer{Data: data, Relaxed: true}
+	got.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error: %v", err)
+	}
+
+	want := MergeConfig{
+		Name:    "api",
+		Port:    8080,
+		Timeout: opt.OInt(30),
+		Server:  MergeServer{Addr: "localhost:8080"},
+		Hosts:   []string{"a.example.com", "b.example.com"},
+		Labels:  map[string]string{"team": "core"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalEasyJSON() = %+v, want %+v", got, want)
+	}
+
+	if err := new(MergeConfig).UnmarshalJSON(data); err == nil {
+		t.Errorf("UnmarshalJSON() without relaxed mode ok; want error")
+	}
+}
+
+func TestRelaxedRawMessage(t *testing.T) {
+	data := []byte(`{Raw: {a: [0x10, 'x',], /* comment */}, Number: 0x10, User: 'ignored'}`)
+
+	var got ConfMarshalers
+	l := jlexer.Lexer{Data: data, Relaxed: true}
+	got.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error: %v", err)
+	}
+
+	if string(got.Raw) != `{"a":[16,"x"]}` {
+		t.Errorf("Raw = %s; want %s", got.Raw, `{"a":[16,"x"]}`)
+	}
+	if got.Number != "16" {
+		t.Errorf("Number = %s; want 16", got.Number)
+	}
+}
@@ -0,0 +1,42 @@
+package tests
+
+import "github.com/19910211/easyjson"
+
+//easyjson:json
+type ResetMissing struct {
+	Name    string            `json:"name"`
+	Count   int               `json:"count"`
+	Enabled bool              `json:"enabled"`
+	Tags    []string          `json:"tags"`
+	Attrs   map[string]string `json:"attrs"`
+	Owner   *ResetMissingUser `json:"owner"`
+	User    ResetMissingUser  `json:"user"`
+	Scores  [2]int            `json:"scores"`
+	Extra   interface{}       `json:"extra"`
+	Cached  string            `json:"-"`
+}
+
+//easyjson:json
+type ResetMissingUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ResetMissingWide has more fields than fit in a single word of the bitmask.
+//
+//easyjson:json
+type ResetMissingWide struct {
+	F0, F1, F2, F3, F4, F5, F6, F7, F8, F9, F10, F11, F12, F13, F14, F15, F16, F17, F18, F19, F20, F21, F22, F23, F24, F25, F26, F27, F28, F29, F30, F31, F32, F33, F34, F35, F36, F37, F38, F39, F40, F41, F42, F43, F44, F45, F46, F47, F48, F49, F50, F51, F52, F53, F54, F55, F56, F57, F58, F59, F60, F61, F62, F63, F64, F65, F66, F67, F68, F69 int
+}
+
+//easyjson:json
+type ResetMissingUnknowns struct {
+	easyjson.UnknownFieldsProxy
+	Name string `json:"name"`
+}
+
+//easyjson:json
+type ResetMissingOrderedUnknowns struct {
+	easyjson.OrderedUnknownFields
+	Name string `json:"name"`
+}
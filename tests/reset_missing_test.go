@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResetMissingFields(t *testing.T) {
+	v := ResetMissing{
+		Name:    "stale",
+		Count:   3,
+		Enabled: true,
+		Tags:    []string{"a"},
+		Attrs:   map[string]string{"k": "v"},
+		Owner:   &ResetMissingUser{ID: 1},
+		User:    ResetMissingUser{ID: 2, Name: "bob"},
+		Scores:  [2]int{1, 2},
+		Extra:   "x",
+		Cached:  "cache",
+	}
+
+	if err := v.UnmarshalJSON([]byte(`{"count":5,"user":{"name":"alice"}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := ResetMissing{
+		Count:  5,
+		User:   ResetMissingUser{Name: "alice"},
+		Cached: "cache",
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalJSON() = %+v, want %+v", v, want)
+	}
+}
+
+func TestResetMissingFieldsWide(t *testing.T) {
+	var v ResetMissingWide
+	v.F0, v.F63, v.F64, v.F69 = 1, 1, 1, 1
+
+	if err := v.UnmarshalJSON([]byte(`{"F63":2,"F65":3}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := ResetMissingWide{F63: 2, F65: 3}
+	if v != want {
+		t.Errorf("UnmarshalJSON() = %+v, want %+v", v, want)
+	}
+}
+
+func TestResetMissingUnknowns(t *testing.T) {
+	var v ResetMissingUnknowns
+	if err := v.UnmarshalJSON([]byte(`{"name":"a","stale":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.UnmarshalJSON([]byte(`{"fresh":2}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := v.Keys(); !reflect.DeepEqual(got, []string{"fresh"}) {
+		t.Errorf("Keys() = %q, want [fresh]", got)
+	}
+	got, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"","fresh":2}`; string(got) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+}
+
+func TestResetMissingOrderedUnknowns(t *testing.T) {
+	var v ResetMissingOrderedUnknowns
+	if err := v.UnmarshalJSON([]byte(`{"stale":1,"name":"a"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.UnmarshalJSON([]byte(`{"fresh":2}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"","fresh":2}`; string(got) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,33 @@
+package tests
+
+import "time"
+
+//easyjson:json
+type SchemaOrder struct {
+	ID       uint64            `json:"id,required"`
+	Customer SchemaCustomer    `json:"customer"`
+	Items    []SchemaItem      `json:"items"`
+	Notes    *string           `json:"notes,omitempty"`
+	Total    int64             `json:"total,string"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Created  time.Time         `json:"created"`
+	Checksum [2]byte           `json:"checksum"`
+	Extra    interface{}       `json:"extra,omitempty"`
+	Parent   *SchemaOrder      `json:"parent,omitempty"`
+	Discount float64           `json:"discount,since=2"`
+	Internal string            `json:"-"`
+}
+
+//easyjson:json
+type SchemaCustomer struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type SchemaItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+//easyjson:json
+type SchemaItems []SchemaItem
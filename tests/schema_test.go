@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{
+			name: "SchemaCustomer",
+			got:  SchemaCustomer{}.JSONSchema(),
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title": "SchemaCustomer",
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"email": {"type": "string"}
+				},
+				"required": ["name"]
+			}`,
+		},
+		{
+			name: "SchemaItems",
+			got:  SchemaItems{}.JSONSchema(),
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title": "SchemaItems",
+				"type": ["array", "null"],
+				"items": {"$ref": "#/$defs/SchemaItem"},
+				"$defs": {
+					"SchemaItem": {
+						"type": "object",
+						"properties": {
+							"sku": {"type": "string"},
+							"quantity": {"type": "integer"}
+						},
+						"required": ["sku", "quantity"]
+					}
+				}
+			}`,
+		},
+		{
+			name: "SchemaOrder",
+			got:  SchemaOrder{}.JSONSchema(),
+			want: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"title": "SchemaOrder",
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer", "minimum": 0},
+					"customer": {"$ref": "#/$defs/SchemaCustomer"},
+					"items": {"type": ["array", "null"], "items": {"$ref": "#/$defs/SchemaItem"}},
+					"notes": {"type": ["string", "null"]},
+					"total": {"type": "string"},
+					"tags": {"type": ["object", "null"], "additionalProperties": {"type": "string"}},
+					"created": {"type": "string", "format": "date-time"},
+					"checksum": {"type": "string", "contentEncoding": "base64"},
+					"extra": {},
+					"parent": {"anyOf": [{"$ref": "#"}, {"type": "null"}]},
+					"discount": {"type": "number"}
+				},
+				"required": ["id", "customer", "items", "total", "created", "checksum"],
+				"$defs": {
+					"SchemaCustomer": {
+						"type": "object",
+						"properties": {
+							"name": {"type": "string"},
+							"email": {"type": "string"}
+						},
+						"required": ["name"]
+					},
+					"SchemaItem": {
+						"type": "object",
+						"properties": {
+							"sku": {"type": "string"},
+							"quantity": {"type": "integer"}
+						},
+						"required": ["sku", "quantity"]
+					}
+				}
+			}`,
+		},
+	} {
+		var got, want interface{}
+		if err := json.Unmarshal(test.got, &got); err != nil {
+			t.Errorf("%s.JSONSchema() is not valid JSON: %v", test.name, err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(test.want), &want); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s.JSONSchema() = %s", test.name, test.got)
+		}
+	}
+}
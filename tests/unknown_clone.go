@@ -0,0 +1,35 @@
+package tests
+
+import "github.com/19910211/easyjson"
+
+//easyjson:pool
+type PooledWithUnknowns struct {
+	easyjson.UnknownFieldsProxy
+
+	Field1 string
+	Items  []string
+}
+
+//easyjson:json
+type ClonedWithUnknowns struct {
+	easyjson.OrderedUnknownFields
+
+	Field1 string
+	Child  *PooledWithUnknowns
+}
+
+// UnknownsChild is not pooled, so the reset of a pooled parent leaves its
+// unknown fields to the garbage collector, as copies of it may share them.
+//
+//easyjson:json
+type UnknownsChild struct {
+	easyjson.UnknownFieldsProxy
+
+	Name string
+}
+
+//easyjson:pool
+type PooledWithChildren struct {
+	Child    UnknownsChild
+	Children []UnknownsChild
+}
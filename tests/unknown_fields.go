@@ -15,3 +15,10 @@ type StructWithUnknownsProxyWithOmitempty struct {
 
 	Field1 string `json:",omitempty"`
 }
+
+//easyjson:json
+type StructWithOrderedUnknowns struct {
+	easyjson.OrderedUnknownFields
+
+	Field1 string
+}
@@ -1,8 +1,13 @@
 package tests
 
 import (
+	"bytes"
+	"encoding/json"
 	"reflect"
 	"testing"
+
+	"github.com/19910211/easyjson"
+	"github.com/19910211/easyjson/jlexer"
 )
 
 func TestUnknownFieldsProxy(t *testing.T) {
@@ -52,3 +57,156 @@ func TestUnknownFieldsProxyWithOmitempty(t *testing.T) {
 		t.Errorf("MarshalJSON expected to gen: %v. got: %v", baseJson, string(data))
 	}
 }
+
+func TestUnknownFieldsProxyAccessors(t *testing.T) {
+	data := []byte(`{"Field1":"123","n":42,"s":"str","o":{"Field1":"x","extra":[1]},"z":null}`)
+
+	var s StructWithUnknownsProxy
+	if err := easyjson.Unmarshal(data, &s); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	defer s.Recycle()
+
+	// The input must not be referenced after decoding.
+	copy(data, bytes.Repeat([]byte(" "), len(data)))
+
+	if got, want := s.Keys(), []string{"n", "o", "s", "z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %q; want %q", got, want)
+	}
+	if v, ok := s.Get("n"); !ok || string(v) != "42" {
+		t.Errorf(`Get("n") = %s, %v; want 42, true`, v, ok)
+	}
+
+	if v, ok, err := easyjson.UnknownAs[int64](&s.UnknownFieldsProxy, "n"); v != 42 || !ok || err != nil {
+		t.Errorf(`UnknownAs[int64]("n") = %v, %v, %v; want 42, true, nil`, v, ok, err)
+	}
+	if v, ok, err := easyjson.UnknownAs[string](&s.UnknownFieldsProxy, "s"); v != "str" || !ok || err != nil {
+		t.Errorf(`UnknownAs[string]("s") = %q, %v, %v; want "str", true, nil`, v, ok, err)
+	}
+	if v, ok, err := easyjson.UnknownAs[float64](&s.UnknownFieldsProxy, "z"); v != 0 || !ok || err != nil {
+		t.Errorf(`UnknownAs[float64]("z") = %v, %v, %v; want 0, true, nil`, v, ok, err)
+	}
+	if _, ok, err := easyjson.UnknownAs[int](&s.UnknownFieldsProxy, "o"); !ok || err == nil {
+		t.Errorf(`UnknownAs[int]("o") = %v, %v; want true, error`, ok, err)
+	}
+	if _, ok, err := easyjson.UnknownAs[int](&s.UnknownFieldsProxy, "missing"); ok || err != nil {
+		t.Errorf(`UnknownAs[int]("missing") = %v, %v; want false, nil`, ok, err)
+	}
+
+	var o StructWithUnknownsProxy
+	if ok, err := s.Decode("o", &o); !ok || err != nil {
+		t.Fatalf(`Decode("o") = %v, %v; want true, nil`, ok, err)
+	}
+	if v, _ := o.Get("extra"); o.Field1 != "x" || string(v) != "[1]" {
+		t.Errorf(`Decode("o") = %+v; want Field1 "x" and extra [1]`, o)
+	}
+	if ok, err := s.Decode("missing", &o); ok || err != nil {
+		t.Errorf(`Decode("missing") = %v, %v; want false, nil`, ok, err)
+	}
+
+	if err := s.Encode("o", StructWithUnknownsProxy{Field1: "y"}); err != nil {
+		t.Fatalf(`Encode("o") error: %v`, err)
+	}
+	s.Set("s", []byte(`"new"`))
+	s.Delete("n", "z", "missing")
+
+	got, err := easyjson.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	var gotMap map[string]interface{}
+	if err := json.Unmarshal(got, &gotMap); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", got, err)
+	}
+	want := map[string]interface{}{"Field1": "123", "o": map[string]interface{}{"Field1": "y"}, "s": "new"}
+	if !reflect.DeepEqual(gotMap, want) {
+		t.Errorf("Marshal() = %s; want %v", got, want)
+	}
+
+	s.Recycle()
+	if keys := s.Keys(); len(keys) != 0 {
+		t.Errorf("Keys() after Recycle() = %q; want none", keys)
+	}
+}
+
+func TestUnknownFieldsProxyLexerOptions(t *testing.T) {
+	data := []byte(`{"b":1,"o":{"Bool":1}}`)
+
+	var s StructWithUnknownsProxy
+	l := jlexer.Lexer{Data: data, Strict: true}
+	s.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error: %v", err)
+	}
+	defer s.Recycle()
+
+	// A strict lexer does not read bools from numbers, so neither do the
+	// accessors of values read with one.
+	if _, ok, err := easyjson.UnknownAs[bool](&s.UnknownFieldsProxy, "b"); !ok || err == nil {
+		t.Errorf(`UnknownAs[bool]("b") = %v, %v; want true, error`, ok, err)
+	}
+	var o ConfScalars
+	if ok, err := s.Decode("o", &o); !ok || err == nil {
+		t.Errorf(`Decode("o") = %v, %v; want true, error`, ok, err)
+	}
+}
+
+func TestUnknownFieldsClone(t *testing.T) {
+	in := `{"Field1":"a","x":1,"Child":{"Field1":"b","Items":["i"],"y":[2]},"x":3}`
+
+	var v ClonedWithUnknowns
+	if err := easyjson.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	c := v.Clone()
+
+	v.Set("x", []byte(`0`))
+	v.Child.Set("y", []byte(`0`))
+	v.Child.Items[0] = "changed"
+	v.Child.ReturnToPool()
+	v.Reset()
+
+	got, err := easyjson.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"Field1":"a","Child":{"Field1":"b","Items":["i"],"y":[2]},"x":1,"x":3}`; string(got) != want {
+		t.Errorf("Marshal() of clone = %s; want %s", got, want)
+	}
+
+	p := PooledWithUnknownsFromPool()
+	if err := easyjson.Unmarshal([]byte(`{"Field1":"b","y":[2]}`), p); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	p.ReturnToPool()
+	if p.Field1 != "" || len(p.Keys()) != 0 {
+		t.Errorf("ReturnToPool() left %+v", *p)
+	}
+}
+
+func TestUnknownFieldsPooledParentCopy(t *testing.T) {
+	p := PooledWithChildrenFromPool()
+	in := `{"Child":{"Name":"a","x":"child"},"Children":[{"Name":"b","y":"element"}]}`
+	if err := easyjson.Unmarshal([]byte(in), p); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+
+	// The children are not pooled, so copies of them stay valid after the
+	// parent is returned to the pool and its storage is reused.
+	child, element := p.Child, p.Children[0]
+	p.ReturnToPool()
+	for i := 0; i < 10; i++ {
+		q := PooledWithUnknownsFromPool()
+		if err := easyjson.Unmarshal([]byte(`{"x":"XXXXXX","y":"YYYYYYYYY"}`), q); err != nil {
+			t.Fatalf("Unmarshal() error: %v", err)
+		}
+		defer q.ReturnToPool()
+	}
+
+	if v, _ := child.Get("x"); string(v) != `"child"` {
+		t.Errorf(`copy of Child: Get("x") = %s; want "child"`, v)
+	}
+	if v, _ := element.Get("y"); string(v) != `"element"` {
+		t.Errorf(`copy of Children[0]: Get("y") = %s; want "element"`, v)
+	}
+}
@@ -0,0 +1,11 @@
+package tests
+
+import "github.com/19910211/easyjson"
+
+//easyjson:json
+type ValueFields struct {
+	Kind    string
+	Payload easyjson.Value
+	Extra   *easyjson.Value
+	List    []*easyjson.Value
+}
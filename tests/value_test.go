@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/19910211/easyjson"
+)
+
+func TestValueFields(t *testing.T) {
+	in := `{"Kind":"k","Payload":{"z":1.0,"a":[1e2,"x"],"z":null},"Extra":null,"List":[true,{}]}`
+
+	var v ValueFields
+	if err := easyjson.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if got, err := v.Payload.Get("/a/0"); err != nil || got.String() != "1e2" {
+		t.Errorf(`Payload.Get("/a/0") = %v, %v; want 1e2, nil`, got, err)
+	}
+
+	got, err := easyjson.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if string(got) != in {
+		t.Errorf("Marshal() = %s; want %s", got, in)
+	}
+}
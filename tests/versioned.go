@@ -0,0 +1,16 @@
+package tests
+
+//easyjson:json
+type VersionedAccount struct {
+	ID       int    `json:"id"`
+	Login    string `json:"login,until=2"`
+	Username string `json:"username,since=3"`
+	Nickname string `json:"nickname,omitempty,since=2,until=4"`
+	Email    string `json:"email,required,since=4"`
+}
+
+//easyjson:json
+type VersionedFirst struct {
+	Legacy string `json:"legacy,until=1"`
+	Name   string `json:"name"`
+}
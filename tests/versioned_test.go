@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+var versionedAccountValue = VersionedAccount{
+	ID:       1,
+	Login:    "alice",
+	Username: "alice",
+	Nickname: "al",
+	Email:    "alice@example.com",
+}
+
+func TestVersionedMarshal(t *testing.T) {
+	for _, test := range []struct {
+		version int
+		want    string
+	}{
+		{0, `{"id":1,"login":"alice","username":"alice","nickname":"al","email":"alice@example.com"}`},
+		{1, `{"id":1,"login":"alice"}`},
+		{2, `{"id":1,"login":"alice","nickname":"al"}`},
+		{3, `{"id":1,"username":"alice","nickname":"al"}`},
+		{5, `{"id":1,"username":"alice","email":"alice@example.com"}`},
+	} {
+		w := jwriter.Writer{Version: test.version}
+		versionedAccountValue.MarshalEasyJSON(&w)
+		got, err := w.BuildBytes()
+		if err != nil {
+			t.Errorf("[%d] MarshalEasyJSON() error: %v", test.version, err)
+		}
+		if string(got) != test.want {
+			t.Errorf("[%d] MarshalEasyJSON() = %s; want %s", test.version, got, test.want)
+		}
+	}
+}
+
+func TestVersionedMarshalFirst(t *testing.T) {
+	v := VersionedFirst{Legacy: "x", Name: "y"}
+	for _, test := range []struct {
+		version int
+		want    string
+	}{
+		{1, `{"legacy":"x","name":"y"}`},
+		{2, `{"name":"y"}`},
+	} {
+		w := jwriter.Writer{Version: test.version}
+		v.MarshalEasyJSON(&w)
+		if got := string(w.Buffer.BuildBytes()); got != test.want {
+			t.Errorf("[%d] MarshalEasyJSON() = %s; want %s", test.version, got, test.want)
+		}
+	}
+}
+
+func TestVersionedUnmarshal(t *testing.T) {
+	data := []byte(`{"id":1,"login":"old","username":"new","nickname":"al"}`)
+
+	for _, test := range []struct {
+		version int
+		want    VersionedAccount
+		wantErr bool
+	}{
+		{0, VersionedAccount{ID: 1, Login: "old", Username: "new", Nickname: "al"}, true},
+		{1, VersionedAccount{ID: 1, Login: "old"}, false},
+		{3, VersionedAccount{ID: 1, Username: "new", Nickname: "al"}, false},
+		{4, VersionedAccount{ID: 1, Username: "new", Nickname: "al"}, true},
+	} {
+		var got VersionedAccount
+		l := jlexer.Lexer{Data: data, Version: test.version}
+		got.UnmarshalEasyJSON(&l)
+		if err := l.Error(); (err != nil) != test.wantErr {
+			t.Errorf("[%d] UnmarshalEasyJSON() error: %v; want error %v", test.version, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("[%d] UnmarshalEasyJSON() = %+v; want %+v", test.version, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package tests
+
+//easyjson:json
+//easyjson:view EventHeader fields=ID,Type,Timestamp
+type Event struct {
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Timestamp int64    `json:"ts"`
+	Payload   []string `json:"payload"`
+	Meta      *Meta    `json:"meta"`
+	Score     float64  `json:"score,required"`
+}
+
+type Meta struct {
+	Source string `json:"source"`
+}
@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/19910211/easyjson/jlexer"
+)
+
+var eventViewData = []byte(`{"payload":["a","b","c"],"id":"42","meta":{"source":"x"},"type":"click","ts":1700000000,"extra":[1,{"x":2}]}`)
+
+func TestView(t *testing.T) {
+	var got Event
+	if err := got.UnmarshalEventHeader(eventViewData); err != nil {
+		t.Fatalf("UnmarshalEventHeader() error: %v", err)
+	}
+
+	want := Event{ID: "42", Type: "click", Timestamp: 1700000000}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalEventHeader() = %+v; want %+v", got, want)
+	}
+
+	l := jlexer.Lexer{Data: []byte(`{"id":"1",`)}
+	got.UnmarshalEasyJSONEventHeader(&l)
+	if l.Error() == nil {
+		t.Errorf("UnmarshalEasyJSONEventHeader() ok; want error")
+	}
+}
+
+func TestViewSkipsAllocations(t *testing.T) {
+	var v Event
+	full := testing.AllocsPerRun(100, func() {
+		v = Event{}
+		_ = v.UnmarshalJSON(eventViewData)
+	})
+	partial := testing.AllocsPerRun(100, func() {
+		v = Event{}
+		_ = v.UnmarshalEventHeader(eventViewData)
+	})
+	if partial >= full {
+		t.Errorf("view decoder allocs = %v; want less than full decoder allocs %v", partial, full)
+	}
+}
@@ -1,21 +1,121 @@
 package easyjson
 
 import (
+	"slices"
+	"strings"
+
+	"github.com/19910211/easyjson/buffer"
 	"github.com/19910211/easyjson/jlexer"
 	"github.com/19910211/easyjson/jwriter"
 )
 
 // UnknownFieldsProxy implemets UnknownsUnmarshaler and UnknownsMarshaler
 // use it as embedded field in your structure to parse and then serialize unknown struct fields
+//
+// The keys and values are copied from the input, the values to chunks from the buffer pool that
+// Recycle returns. A copy of the struct shares the chunks, so it is invalidated when either one is
+// recycled, e.g. by the reset of a pooled struct; use Clone for copies that outlive the original.
+// Decode and UnknownAs decode the values with the options of the lexer they were
+// read with, such as the limits, the strict mode and the context.
 type UnknownFieldsProxy struct {
 	unknownFields map[string][]byte
+	chunks        [][]byte
+	from          *jlexer.Lexer // Lexer with the options of the input, see lexer.
 }
 
-func (s *UnknownFieldsProxy) UnmarshalUnknown(in *jlexer.Lexer, key string) {
+// Keys returns the keys of the unknown fields in sorted order.
+func (s *UnknownFieldsProxy) Keys() []string {
+	keys := make([]string, 0, len(s.unknownFields))
+	for key := range s.unknownFields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Get returns the raw value of the unknown field key. The value must not be modified and is valid
+// until the field is changed or s is recycled.
+func (s *UnknownFieldsProxy) Get(key string) ([]byte, bool) {
+	data, ok := s.unknownFields[key]
+	return data, ok
+}
+
+// Decode decodes the value of the unknown field key into v and reports whether the field is
+// present.
+func (s *UnknownFieldsProxy) Decode(key string, v Unmarshaler) (bool, error) {
+	data, ok := s.unknownFields[key]
+	if !ok {
+		return false, nil
+	}
+	l := s.lexer(data)
+	v.UnmarshalEasyJSON(l)
+	return true, l.Error()
+}
+
+// lexer returns a lexer for data with the options of the lexer the unknown fields were read with.
+func (s *UnknownFieldsProxy) lexer(data []byte) *jlexer.Lexer {
+	if s.from == nil {
+		return &jlexer.Lexer{Data: data}
+	}
+	return s.from.Derive(data)
+}
+
+// Set sets the raw value of the unknown field key. The value is copied and must be valid JSON.
+func (s *UnknownFieldsProxy) Set(key string, raw []byte) {
 	if s.unknownFields == nil {
 		s.unknownFields = make(map[string][]byte, 1)
 	}
-	s.unknownFields[key] = in.Raw()
+	if _, ok := s.unknownFields[key]; !ok {
+		key = strings.Clone(key)
+	}
+	s.unknownFields[key] = storeChunk(&s.chunks, raw)
+}
+
+// Encode sets the unknown field key to v encoded.
+func (s *UnknownFieldsProxy) Encode(key string, v Marshaler) error {
+	w := jwriter.Writer{}
+	v.MarshalEasyJSON(&w)
+	if w.Error != nil {
+		return w.Error
+	}
+	s.Set(key, w.Buffer.BuildBytes())
+	return nil
+}
+
+// Delete removes the unknown fields with the keys.
+func (s *UnknownFieldsProxy) Delete(keys ...string) {
+	for _, key := range keys {
+		delete(s.unknownFields, key)
+	}
+}
+
+// Clone returns a copy of s with its own storage.
+func (s *UnknownFieldsProxy) Clone() *UnknownFieldsProxy {
+	r := &UnknownFieldsProxy{from: s.from}
+	if len(s.unknownFields) > 0 {
+		r.unknownFields = make(map[string][]byte, len(s.unknownFields))
+		for key, val := range s.unknownFields {
+			r.unknownFields[key] = storeChunk(&r.chunks, val)
+		}
+	}
+	return r
+}
+
+// Recycle removes all the unknown fields and returns their storage to the pool. The values
+// returned before must not be used after. It is called by the reset of generated pooled structs.
+func (s *UnknownFieldsProxy) Recycle() {
+	clear(s.unknownFields)
+	putChunks(&s.chunks)
+	s.from = nil
+}
+
+func (s *UnknownFieldsProxy) UnmarshalUnknown(in *jlexer.Lexer, key string) {
+	if data := in.Raw(); in.Ok() {
+		if s.from == nil {
+			s.from = in.Derive(nil)
+		}
+		s.Set(key, data)
+	}
 }
 
 func (s UnknownFieldsProxy) MarshalUnknowns(out *jwriter.Writer, first bool) {
@@ -30,3 +130,232 @@ func (s UnknownFieldsProxy) MarshalUnknowns(out *jwriter.Writer, first bool) {
 		out.Raw(val, nil)
 	}
 }
+
+// Primitive is the set of types UnknownAs decodes.
+type Primitive interface {
+	bool | string | int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | float32 | float64
+}
+
+// UnknownAs decodes the value of the unknown field key of s as T and reports whether the field is
+// present. A null value decodes as the zero value.
+func UnknownAs[T Primitive](s *UnknownFieldsProxy, key string) (v T, ok bool, err error) {
+	data, ok := s.unknownFields[key]
+	if !ok {
+		return v, false, nil
+	}
+	l := s.lexer(data)
+	if l.IsNull() {
+		l.Skip()
+	} else {
+		switch p := any(&v).(type) {
+		case *bool:
+			*p = l.Bool()
+		case *string:
+			*p = l.String()
+		case *int:
+			*p = l.Int()
+		case *int8:
+			*p = l.Int8()
+		case *int16:
+			*p = l.Int16()
+		case *int32:
+			*p = l.Int32()
+		case *int64:
+			*p = l.Int64()
+		case *uint:
+			*p = l.Uint()
+		case *uint8:
+			*p = l.Uint8()
+		case *uint16:
+			*p = l.Uint16()
+		case *uint32:
+			*p = l.Uint32()
+		case *uint64:
+			*p = l.Uint64()
+		case *float32:
+			*p = l.Float32()
+		case *float64:
+			*p = l.Float64()
+		}
+	}
+	l.Consumed()
+	return v, true, l.Error()
+}
+
+// storeChunk copies data to the chunks from the buffer pool.
+func storeChunk(chunks *[][]byte, data []byte) []byte {
+	if n := len(*chunks); n > 0 {
+		if c := (*chunks)[n-1]; cap(c)-len(c) >= len(data) {
+			(*chunks)[n-1] = append(c, data...)
+			return c[len(c) : len(c)+len(data) : len(c)+len(data)]
+		}
+	}
+	c := append(buffer.GetChunk(len(data)), data...)
+	*chunks = append(*chunks, c)
+	return c[:len(data):len(data)]
+}
+
+// putChunks returns the chunks to the buffer pool.
+func putChunks(chunks *[][]byte) {
+	for _, c := range *chunks {
+		buffer.PutChunk(c)
+	}
+	clear(*chunks)
+	*chunks = (*chunks)[:0]
+}
+
+// UnknownField is an object member that did not match any field of a struct.
+type UnknownField struct {
+	Key   string
+	Value []byte // Raw JSON of the value.
+
+	after  string // Key of the member the field followed, if placed.
+	placed bool
+}
+
+// OrderedUnknownFields implements UnknownsUnmarshaler and UnknownsMarshaler keeping the unknown
+// fields in the order they appeared in, including the repeated keys, and their values byte for
+// byte. Use it as embedded field in your structure to pass unknown data through unchanged.
+//
+// With the -ordered_unknowns option of the generator the fields are also written back in their
+// original position among the known fields.
+//
+// The values are stored in chunks from the buffer pool, call Reset to return them once the
+// structure is no longer used.
+type OrderedUnknownFields struct {
+	fields []UnknownField
+	chunks [][]byte
+}
+
+// Len returns the number of unknown fields.
+func (s *OrderedUnknownFields) Len() int {
+	return len(s.fields)
+}
+
+// Fields returns the unknown fields in order. The slice must not be modified and is valid until
+// the next change of s.
+func (s *OrderedUnknownFields) Fields() []UnknownField {
+	return s.fields
+}
+
+// Get returns the raw value of the last field with the key, the one encoding/json would decode.
+func (s *OrderedUnknownFields) Get(key string) ([]byte, bool) {
+	for i := len(s.fields) - 1; i >= 0; i-- {
+		if s.fields[i].Key == key {
+			return s.fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Set sets the raw value of the field with the key, keeping its position and dropping the
+// repeated ones. A new field is added after all the others. The value is copied and must be valid
+// JSON.
+func (s *OrderedUnknownFields) Set(key string, raw []byte) {
+	for i := range s.fields {
+		if s.fields[i].Key == key {
+			s.fields[i].Value = storeChunk(&s.chunks, raw)
+			s.remove(key, i+1)
+			return
+		}
+	}
+	s.fields = append(s.fields, UnknownField{Key: key, Value: storeChunk(&s.chunks, raw)})
+}
+
+// Delete removes all the fields with the key.
+func (s *OrderedUnknownFields) Delete(key string) {
+	s.remove(key, 0)
+}
+
+// Reset removes all the fields and returns their storage to the pool. The values returned before
+// must not be used after.
+func (s *OrderedUnknownFields) Reset() {
+	clear(s.fields)
+	s.fields = s.fields[:0]
+	putChunks(&s.chunks)
+}
+
+// Recycle is Reset, called by the reset of generated pooled structs.
+func (s *OrderedUnknownFields) Recycle() {
+	s.Reset()
+}
+
+// Clone returns a copy of s with its own storage.
+func (s *OrderedUnknownFields) Clone() *OrderedUnknownFields {
+	r := &OrderedUnknownFields{}
+	if len(s.fields) > 0 {
+		r.fields = make([]UnknownField, len(s.fields))
+		for i, f := range s.fields {
+			f.Value = storeChunk(&r.chunks, f.Value)
+			r.fields[i] = f
+		}
+	}
+	return r
+}
+
+// remove removes the fields with the key starting from index i.
+func (s *OrderedUnknownFields) remove(key string, i int) {
+	n := i
+	for ; i < len(s.fields); i++ {
+		if s.fields[i].Key != key {
+			s.fields[n] = s.fields[i]
+			n++
+		}
+	}
+	clear(s.fields[n:])
+	s.fields = s.fields[:n]
+}
+
+func (s *OrderedUnknownFields) UnmarshalUnknown(in *jlexer.Lexer, key string) {
+	if data := in.Raw(); in.Ok() {
+		s.fields = append(s.fields, UnknownField{Key: strings.Clone(key), Value: storeChunk(&s.chunks, data)})
+	}
+}
+
+func (s *OrderedUnknownFields) UnmarshalUnknownAfter(in *jlexer.Lexer, key, prev string) {
+	data := in.Raw()
+	if !in.Ok() {
+		return
+	}
+	var after string
+	if n := len(s.fields); n > 0 && s.fields[n-1].placed && s.fields[n-1].Key == prev {
+		// The field follows another unknown one, so it goes to the same place.
+		after = s.fields[n-1].after
+	} else {
+		after = strings.Clone(prev)
+	}
+	s.fields = append(s.fields, UnknownField{Key: strings.Clone(key), Value: storeChunk(&s.chunks, data), after: after, placed: true})
+}
+
+func (s OrderedUnknownFields) MarshalUnknowns(out *jwriter.Writer, first bool) {
+	for _, f := range s.fields {
+		first = writeUnknown(out, f, first)
+	}
+}
+
+func (s OrderedUnknownFields) MarshalUnknownsAfter(out *jwriter.Writer, prev string, first bool) bool {
+	for _, f := range s.fields {
+		if f.placed && f.after == prev {
+			first = writeUnknown(out, f, first)
+		}
+	}
+	return first
+}
+
+func (s OrderedUnknownFields) MarshalUnplacedUnknowns(out *jwriter.Writer, first bool) {
+	for _, f := range s.fields {
+		if !f.placed {
+			first = writeUnknown(out, f, first)
+		}
+	}
+}
+
+func writeUnknown(out *jwriter.Writer, f UnknownField, first bool) bool {
+	if !first {
+		out.RawByte(',')
+	}
+	out.String(f.Key)
+	out.RawByte(':')
+	out.Raw(f.Value, nil)
+	return false
+}
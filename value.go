@@ -0,0 +1,579 @@
+package easyjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/19910211/easyjson/jlexer"
+	"github.com/19910211/easyjson/jwriter"
+)
+
+// ValueKind is the JSON type of a Value.
+type ValueKind byte
+
+const (
+	NullValue ValueKind = iota
+	BoolValue
+	NumberValue
+	StringValue
+	ArrayValue
+	ObjectValue
+)
+
+var valueKindNames = [...]string{
+	NullValue:   "null",
+	BoolValue:   "bool",
+	NumberValue: "number",
+	StringValue: "string",
+	ArrayValue:  "array",
+	ObjectValue: "object",
+}
+
+func (k ValueKind) String() string {
+	if int(k) < len(valueKindNames) {
+		return valueKindNames[k]
+	}
+	return "ValueKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Member is a member of an object Value.
+type Member struct {
+	Key   string
+	Value *Value
+}
+
+// Value is a mutable tree of a JSON value of any shape, for payloads with no
+// schema to generate code for. Unlike the result of jlexer.Lexer.Interface(),
+// objects keep their members in order, repeated keys included, and numbers are
+// kept as their literals, so a decoded Value is encoded back with no loss of
+// precision.
+//
+// The zero Value is null. Decoding allocates the nodes from a pool; Recycle
+// returns the nodes under a value to it.
+type Value struct {
+	kind    ValueKind
+	b       bool
+	s       string // String value or number literal.
+	items   []*Value
+	members []Member
+}
+
+var valuePool = sync.Pool{New: func() any { return &Value{} }}
+
+// ValueFromPool returns a null Value from the pool. Return it with ReturnToPool.
+func ValueFromPool() *Value {
+	return valuePool.Get().(*Value)
+}
+
+// ReturnToPool recycles v and puts it to the pool. Neither v nor the values
+// under it must be used after.
+func (v *Value) ReturnToPool() {
+	if v != nil {
+		v.Recycle()
+		valuePool.Put(v)
+	}
+}
+
+// Recycle returns the values under v to the pool and sets v to null. The
+// values under v must not be used after.
+func (v *Value) Recycle() {
+	for _, item := range v.items {
+		item.ReturnToPool()
+	}
+	for _, m := range v.members {
+		m.Value.ReturnToPool()
+	}
+	clear(v.items)
+	clear(v.members)
+	*v = Value{items: v.items[:0], members: v.members[:0]}
+}
+
+// NewBool returns a bool Value from the pool.
+func NewBool(b bool) *Value {
+	v := ValueFromPool()
+	v.SetBool(b)
+	return v
+}
+
+// NewString returns a string Value from the pool.
+func NewString(s string) *Value {
+	v := ValueFromPool()
+	v.SetString(s)
+	return v
+}
+
+// NewInt returns a number Value from the pool.
+func NewInt(n int64) *Value {
+	v := ValueFromPool()
+	v.SetInt(n)
+	return v
+}
+
+// NewArray returns an array Value from the pool holding items.
+func NewArray(items ...*Value) *Value {
+	v := ValueFromPool()
+	v.SetArray(items...)
+	return v
+}
+
+// NewObject returns an object Value from the pool holding members.
+func NewObject(members ...Member) *Value {
+	v := ValueFromPool()
+	v.SetObject(members...)
+	return v
+}
+
+// Kind returns the JSON type of v. A nil Value is null.
+func (v *Value) Kind() ValueKind {
+	if v == nil {
+		return NullValue
+	}
+	return v.kind
+}
+
+// Bool returns the value of a bool Value.
+func (v *Value) Bool() (b, ok bool) {
+	if v.Kind() != BoolValue {
+		return false, false
+	}
+	return v.b, true
+}
+
+// Str returns the value of a string Value.
+func (v *Value) Str() (string, bool) {
+	if v.Kind() != StringValue {
+		return "", false
+	}
+	return v.s, true
+}
+
+// Number returns the literal of a number Value.
+func (v *Value) Number() (json.Number, bool) {
+	if v.Kind() != NumberValue {
+		return "", false
+	}
+	return json.Number(v.s), true
+}
+
+// Int64 returns the value of a number Value that is an integer.
+func (v *Value) Int64() (int64, error) {
+	if v.Kind() != NumberValue {
+		return 0, fmt.Errorf("easyjson: %v value is not a number", v.Kind())
+	}
+	return strconv.ParseInt(v.s, 10, 64)
+}
+
+// Float64 returns the value of a number Value.
+func (v *Value) Float64() (float64, error) {
+	if v.Kind() != NumberValue {
+		return 0, fmt.Errorf("easyjson: %v value is not a number", v.Kind())
+	}
+	return strconv.ParseFloat(v.s, 64)
+}
+
+// Len returns the number of elements of an array or members of an object.
+func (v *Value) Len() int {
+	switch v.Kind() {
+	case ArrayValue:
+		return len(v.items)
+	case ObjectValue:
+		return len(v.members)
+	}
+	return 0
+}
+
+// Items returns the elements of an array. The slice must not be modified.
+func (v *Value) Items() []*Value {
+	if v.Kind() != ArrayValue {
+		return nil
+	}
+	return v.items
+}
+
+// Members returns the members of an object in order. The slice must not be
+// modified.
+func (v *Value) Members() []Member {
+	if v.Kind() != ObjectValue {
+		return nil
+	}
+	return v.members
+}
+
+// Index returns the element i of an array, or nil if there is none.
+func (v *Value) Index(i int) *Value {
+	if v.Kind() != ArrayValue || i < 0 || i >= len(v.items) {
+		return nil
+	}
+	return v.items[i]
+}
+
+// Member returns the value of the first member of an object with the key, or
+// nil if there is none.
+func (v *Value) Member(key string) *Value {
+	if i := v.find(key); i >= 0 {
+		return v.members[i].Value
+	}
+	return nil
+}
+
+// find returns the index of the first member with the key or -1.
+func (v *Value) find(key string) int {
+	if v.Kind() != ObjectValue {
+		return -1
+	}
+	for i, m := range v.members {
+		if m.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetNull sets v to null, recycling the values under it.
+func (v *Value) SetNull() {
+	v.Recycle()
+}
+
+// SetBool sets v to a bool.
+func (v *Value) SetBool(b bool) {
+	v.Recycle()
+	v.kind, v.b = BoolValue, b
+}
+
+// SetString sets v to a string.
+func (v *Value) SetString(s string) {
+	v.Recycle()
+	v.kind, v.s = StringValue, s
+}
+
+// SetInt sets v to an integer number.
+func (v *Value) SetInt(n int64) {
+	v.Recycle()
+	v.kind, v.s = NumberValue, strconv.FormatInt(n, 10)
+}
+
+// SetFloat sets v to a number written as jwriter.Writer writes float64. NaN
+// and infinities are not valid JSON numbers and are reported as errors.
+func (v *Value) SetFloat(f float64) error {
+	w := jwriter.Writer{Flags: jwriter.NonFiniteError}
+	w.Float64(f)
+	if w.Error != nil {
+		return w.Error
+	}
+	v.Recycle()
+	v.kind, v.s = NumberValue, string(w.Buffer.BuildBytes())
+	return nil
+}
+
+// SetNumber sets v to a number with the literal n, which must be a valid JSON
+// number.
+func (v *Value) SetNumber(n json.Number) error {
+	if !jlexer.ValidNumber([]byte(n)) {
+		return fmt.Errorf("easyjson: invalid number literal %q", string(n))
+	}
+	v.Recycle()
+	v.kind, v.s = NumberValue, string(n)
+	return nil
+}
+
+// SetArray sets v to an array of items, which v then owns.
+func (v *Value) SetArray(items ...*Value) {
+	v.Recycle()
+	v.kind = ArrayValue
+	v.items = append(v.items, items...)
+}
+
+// SetObject sets v to an object with members, which v then owns.
+func (v *Value) SetObject(members ...Member) {
+	v.Recycle()
+	v.kind = ObjectValue
+	v.members = append(v.members, members...)
+}
+
+// Append appends items to an array, which v then owns.
+func (v *Value) Append(items ...*Value) {
+	if v.kind != ArrayValue {
+		v.SetArray()
+	}
+	v.items = append(v.items, items...)
+}
+
+// SetMember sets the value of the first member of an object with the key,
+// recycling the old value, or appends a new member if there is none. v then
+// owns the value.
+func (v *Value) SetMember(key string, value *Value) {
+	if v.kind != ObjectValue {
+		v.SetObject()
+	}
+	if i := v.find(key); i >= 0 {
+		v.members[i].Value.ReturnToPool()
+		v.members[i].Value = value
+		return
+	}
+	v.members = append(v.members, Member{Key: key, Value: value})
+}
+
+// DeleteMember removes all the members of an object with the key, recycling
+// their values.
+func (v *Value) DeleteMember(key string) {
+	if v.Kind() != ObjectValue {
+		return
+	}
+	n := 0
+	for _, m := range v.members {
+		if m.Key == key {
+			m.Value.ReturnToPool()
+			continue
+		}
+		v.members[n] = m
+		n++
+	}
+	clear(v.members[n:])
+	v.members = v.members[:n]
+}
+
+// Clone returns a deep copy of v with the nodes from the pool.
+func (v *Value) Clone() *Value {
+	if v == nil {
+		return nil
+	}
+	c := ValueFromPool()
+	c.kind, c.b, c.s = v.kind, v.b, v.s
+	for _, item := range v.items {
+		c.items = append(c.items, item.Clone())
+	}
+	for _, m := range v.members {
+		c.members = append(c.members, Member{Key: m.Key, Value: m.Value.Clone()})
+	}
+	return c
+}
+
+// Get returns the value referenced by the JSON Pointer (RFC 6901) ptr, e.g.
+// "/items/0/name". If an object contains the same key several times, the first
+// occurrence is used, as by LookupRaw.
+func (v *Value) Get(ptr string) (*Value, error) {
+	tokens, err := parseValuePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	for _, tok := range tokens {
+		if v = v.child(tok); v == nil {
+			return nil, ErrPointerNotFound
+		}
+	}
+	return v, nil
+}
+
+// Set replaces the value referenced by the JSON Pointer ptr with value,
+// recycling the old one, and v then owns value. The last token of ptr may also
+// name a new member of an object, or the end of an array as "-" or its length,
+// to append. The empty pointer moves the contents of value to v itself, leaving
+// value null.
+func (v *Value) Set(ptr string, value *Value) error {
+	tokens, err := parseValuePointer(ptr)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		if value != v {
+			v.Recycle()
+			*v, *value = *value, Value{}
+		}
+		return nil
+	}
+
+	parent := v
+	for _, tok := range tokens[:len(tokens)-1] {
+		if parent = parent.child(tok); parent == nil {
+			return ErrPointerNotFound
+		}
+	}
+
+	tok := tokens[len(tokens)-1]
+	switch parent.Kind() {
+	case ObjectValue:
+		parent.SetMember(tok, value)
+		return nil
+	case ArrayValue:
+		if tok == "-" || tok == strconv.Itoa(len(parent.items)) {
+			parent.items = append(parent.items, value)
+			return nil
+		}
+		i, ok := valuePointerIndex(tok)
+		if !ok || i >= len(parent.items) {
+			return ErrPointerNotFound
+		}
+		parent.items[i].ReturnToPool()
+		parent.items[i] = value
+		return nil
+	}
+	return ErrPointerNotFound
+}
+
+// child returns the member or element referenced by a single pointer token.
+func (v *Value) child(tok string) *Value {
+	switch v.Kind() {
+	case ObjectValue:
+		return v.Member(tok)
+	case ArrayValue:
+		if i, ok := valuePointerIndex(tok); ok {
+			return v.Index(i)
+		}
+	}
+	return nil
+}
+
+// parseValuePointer splits a JSON Pointer into unescaped reference tokens.
+func parseValuePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("easyjson: invalid JSON pointer %q", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		if !strings.Contains(tok, "~") {
+			continue
+		}
+		for j := 0; j < len(tok); j++ {
+			if tok[j] == '~' && (j+1 == len(tok) || (tok[j+1] != '0' && tok[j+1] != '1')) {
+				return nil, fmt.Errorf("easyjson: invalid JSON pointer %q", ptr)
+			}
+		}
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+	}
+	return tokens, nil
+}
+
+// valuePointerIndex parses an array index token: a decimal number without
+// leading zeros.
+func valuePointerIndex(tok string) (int, bool) {
+	if tok == "" || len(tok) > 9 || (len(tok) > 1 && tok[0] == '0') {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+// MarshalEasyJSON does JSON marshaling using easyjson interface.
+func (v *Value) MarshalEasyJSON(w *jwriter.Writer) {
+	switch v.Kind() {
+	case NullValue:
+		w.RawString("null")
+	case BoolValue:
+		w.Bool(v.b)
+	case NumberValue:
+		w.RawString(v.s)
+	case StringValue:
+		w.String(v.s)
+	case ArrayValue:
+		w.RawByte('[')
+		for i, item := range v.items {
+			if i > 0 {
+				w.RawByte(',')
+			}
+			item.MarshalEasyJSON(w)
+		}
+		w.RawByte(']')
+	case ObjectValue:
+		w.RawByte('{')
+		for i, m := range v.members {
+			if i > 0 {
+				w.RawByte(',')
+			}
+			w.String(m.Key)
+			w.RawByte(':')
+			m.Value.MarshalEasyJSON(w)
+		}
+		w.RawByte('}')
+	}
+}
+
+// UnmarshalEasyJSON does JSON unmarshaling using easyjson interface. The
+// values under v are recycled first.
+func (v *Value) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	isTopLevel := l.IsStart()
+	v.Recycle()
+	v.unmarshal(l)
+	if isTopLevel {
+		l.Consumed()
+	}
+}
+
+func (v *Value) unmarshal(l *jlexer.Lexer) {
+
+	switch l.CurrentToken() {
+	case jlexer.TokenNull:
+		l.Null()
+	case jlexer.TokenBool:
+		v.kind, v.b = BoolValue, l.Bool()
+	case jlexer.TokenNumber:
+		// Literals are written back as is, so the ones only a lenient lexer
+		// accepts are rejected.
+		pos := l.GetPos()
+		raw := l.Raw()
+		if l.Ok() && !jlexer.ValidNumber(raw) {
+			l.AddError(&jlexer.LexerError{
+				Reason: "invalid number literal",
+				Offset: pos,
+				Data:   string(raw),
+			})
+			return
+		}
+		v.kind, v.s = NumberValue, string(raw)
+	case jlexer.TokenString:
+		v.kind, v.s = StringValue, l.String()
+	case jlexer.TokenDelim:
+		if l.IsDelim('[') {
+			v.kind = ArrayValue
+			l.Delim('[')
+			for !l.IsDelim(']') {
+				item := ValueFromPool()
+				item.unmarshal(l)
+				v.items = append(v.items, item)
+				l.WantComma()
+			}
+			l.Delim(']')
+			return
+		}
+		v.kind = ObjectValue
+		l.Delim('{')
+		for !l.IsDelim('}') {
+			key := l.String()
+			l.WantColon()
+			item := ValueFromPool()
+			item.unmarshal(l)
+			v.members = append(v.members, Member{Key: key, Value: item})
+			l.WantComma()
+		}
+		l.Delim('}')
+	}
+}
+
+// MarshalJSON implements encoding/json.Marshaler interface.
+func (v *Value) MarshalJSON() ([]byte, error) {
+	return Marshal(v)
+}
+
+// UnmarshalJSON implements encoding/json.Unmarshaler interface.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	return Unmarshal(data, v)
+}
+
+// String returns the JSON encoding of v.
+func (v *Value) String() string {
+	w := jwriter.Writer{}
+	v.MarshalEasyJSON(&w)
+	return string(w.Buffer.BuildBytes())
+}
@@ -0,0 +1,230 @@
+package easyjson
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValueRoundTrip(t *testing.T) {
+	for i, test := range []string{
+		`null`,
+		`true`,
+		`"str"`,
+		`12345678901234567890123`,
+		`[]`,
+		`{}`,
+		`[1,2.50,-0,1e400,{"a":[]}]`,
+		`{"b":1,"a":{"x":null,"y":false},"b":"dup"}`,
+	} {
+		var v Value
+		if err := Unmarshal([]byte(test), &v); err != nil {
+			t.Errorf("[%d] Unmarshal(%s) error: %v", i, test, err)
+			continue
+		}
+		got, err := Marshal(&v)
+		if err != nil {
+			t.Errorf("[%d] Marshal() error: %v", i, err)
+		}
+		if string(got) != test {
+			t.Errorf("[%d] Marshal() = %s; want %s", i, got, test)
+		}
+		v.Recycle()
+	}
+
+	var v Value
+	for i, test := range []string{`{"a":}`, `[1,]`, `{} x`, `nul`, `01`, `[1.]`, `{"a":-}`, `1e`} {
+		if err := Unmarshal([]byte(test), &v); err == nil {
+			t.Errorf("[%d] Unmarshal(%s) ok; want error", i, test)
+		}
+	}
+
+	if err := Unmarshal([]byte(` [ 1 , {"a" : -0.5e+1 } ] `), &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if got := v.String(); got != `[1,{"a":-0.5e+1}]` {
+		t.Errorf("String() = %s; want %s", got, `[1,{"a":-0.5e+1}]`)
+	}
+
+	if err := Unmarshal([]byte(`"aé\"\n"`), &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if s, ok := v.Str(); !ok || s != "aé\"\n" {
+		t.Errorf("Str() = %q, %v; want %q, true", s, ok, "aé\"\n")
+	}
+}
+
+func TestValueAccessors(t *testing.T) {
+	var v Value
+	if err := Unmarshal([]byte(`{"n":12345678901234567890,"f":1.5,"i":-7,"b":true,"s":"x","a":[1,2]}`), &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	defer v.Recycle()
+
+	if v.Kind() != ObjectValue || v.Len() != 6 {
+		t.Errorf("Kind(), Len() = %v, %d; want object, 6", v.Kind(), v.Len())
+	}
+	if n, ok := v.Member("n").Number(); !ok || n != "12345678901234567890" {
+		t.Errorf("Number() = %v, %v; want 12345678901234567890, true", n, ok)
+	}
+	if _, err := v.Member("n").Int64(); err == nil {
+		t.Errorf("Int64() of 12345678901234567890 ok; want error")
+	}
+	if i, err := v.Member("i").Int64(); err != nil || i != -7 {
+		t.Errorf("Int64() = %v, %v; want -7, nil", i, err)
+	}
+	if f, err := v.Member("f").Float64(); err != nil || f != 1.5 {
+		t.Errorf("Float64() = %v, %v; want 1.5, nil", f, err)
+	}
+	if b, ok := v.Member("b").Bool(); !ok || !b {
+		t.Errorf("Bool() = %v, %v; want true, true", b, ok)
+	}
+	if _, ok := v.Member("s").Bool(); ok {
+		t.Errorf("Bool() of a string ok")
+	}
+	if _, err := v.Member("s").Float64(); err == nil {
+		t.Errorf("Float64() of a string ok; want error")
+	}
+	if got := v.Member("a").Index(1).String(); got != "2" {
+		t.Errorf("Index(1) = %s; want 2", got)
+	}
+	if v.Member("missing") != nil || v.Member("a").Index(2) != nil || v.Member("missing").Kind() != NullValue {
+		t.Errorf("missing members and elements are not nil")
+	}
+
+	v.DeleteMember("n")
+	v.SetMember("s", NewString("y"))
+	v.SetMember("new", NewArray(NewInt(1), NewBool(false)))
+	v.Member("a").Append(NewObject(Member{Key: "k", Value: NewString("v")}))
+	if err := v.Member("f").SetNumber("2e3"); err != nil {
+		t.Errorf("SetNumber() error: %v", err)
+	}
+	if err := v.Member("i").SetFloat(0.25); err != nil {
+		t.Errorf("SetFloat() error: %v", err)
+	}
+	want := `{"f":2e3,"i":0.25,"b":true,"s":"y","a":[1,2,{"k":"v"}],"new":[1,false]}`
+	if got := v.String(); got != want {
+		t.Errorf("String() = %s; want %s", got, want)
+	}
+
+	for _, n := range []json.Number{"", "01", "1.", "abc", "1 2", `"1"`} {
+		if err := v.Member("f").SetNumber(n); err == nil {
+			t.Errorf("SetNumber(%q) ok; want error", n)
+		}
+	}
+	if err := v.Member("f").SetFloat(1 / zero()); err == nil {
+		t.Errorf("SetFloat(+Inf) ok; want error")
+	}
+	if got := v.Member("f").String(); got != "2e3" {
+		t.Errorf("failed Set changed value to %s", got)
+	}
+}
+
+func zero() float64 { return 0 }
+
+func TestValuePointer(t *testing.T) {
+	var v Value
+	if err := Unmarshal([]byte(`{"a":[{"b":1},{"c/d":2,"e~f":3}],"a":"dup"}`), &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	defer v.Recycle()
+
+	for _, test := range []struct {
+		ptr, want string
+	}{
+		{"", `{"a":[{"b":1},{"c/d":2,"e~f":3}],"a":"dup"}`},
+		{"/a", `[{"b":1},{"c/d":2,"e~f":3}]`},
+		{"/a/0/b", `1`},
+		{"/a/1/c~1d", `2`},
+		{"/a/1/e~0f", `3`},
+	} {
+		got, err := v.Get(test.ptr)
+		if err != nil {
+			t.Errorf("Get(%q) error: %v", test.ptr, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("Get(%q) = %s; want %s", test.ptr, got, test.want)
+		}
+	}
+	for _, ptr := range []string{"/x", "/a/2", "/a/01", "/a/-", "/a/0/b/c"} {
+		if _, err := v.Get(ptr); err != ErrPointerNotFound {
+			t.Errorf("Get(%q) error = %v; want %v", ptr, err, ErrPointerNotFound)
+		}
+	}
+	for _, ptr := range []string{"a", "/a~2"} {
+		if _, err := v.Get(ptr); err == nil || err == ErrPointerNotFound {
+			t.Errorf("Get(%q) error = %v; want invalid pointer", ptr, err)
+		}
+	}
+
+	for _, test := range []struct {
+		ptr, value string
+	}{
+		{"/a/0/b", `"replaced"`},
+		{"/a/0/new", `null`},
+		{"/a/-", `4`},
+		{"/a/3", `5`},
+		{"/x", `{}`},
+		{"/x/y", `[true]`},
+	} {
+		var nv Value
+		if err := Unmarshal([]byte(test.value), &nv); err != nil {
+			t.Fatalf("Unmarshal() error: %v", err)
+		}
+		n := ValueFromPool()
+		n.Set("", &nv)
+		if err := v.Set(test.ptr, n); err != nil {
+			t.Errorf("Set(%q) error: %v", test.ptr, err)
+		}
+	}
+	want := `{"a":[{"b":"replaced","new":null},{"c/d":2,"e~f":3},4,5],"a":"dup","x":{"y":[true]}}`
+	if got := v.String(); got != want {
+		t.Errorf("String() after Set() = %s; want %s", got, want)
+	}
+
+	for _, ptr := range []string{"/a/9", "/missing/x", "/a/0/b/c", "x"} {
+		if err := v.Set(ptr, NewInt(0)); err == nil {
+			t.Errorf("Set(%q) ok; want error", ptr)
+		}
+	}
+}
+
+func TestValueClone(t *testing.T) {
+	v := ValueFromPool()
+	if err := Unmarshal([]byte(`{"a":[1,{"b":"c"}]}`), v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	c := v.Clone()
+	v.Member("a").Index(1).SetMember("b", NewString("changed"))
+	v.ReturnToPool()
+
+	if got, want := c.String(), `{"a":[1,{"b":"c"}]}`; got != want {
+		t.Errorf("Clone() = %s; want %s", got, want)
+	}
+
+	// Decoding into a used value drops its old contents.
+	if err := Unmarshal([]byte(`[true]`), c); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if got := c.String(); got != `[true]` {
+		t.Errorf("Unmarshal() into used value = %s; want [true]", got)
+	}
+	c.ReturnToPool()
+}
+
+func TestValueEncodingJSON(t *testing.T) {
+	var s struct {
+		V *Value `json:"v"`
+	}
+	in := `{"v":{"z":1.10,"a":[null]}}`
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	got, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if string(got) != in {
+		t.Errorf("json.Marshal() = %s; want %s", got, in)
+	}
+}